@@ -0,0 +1,140 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"n1kit0s/vt-manager/app/github"
+	"n1kit0s/vt-manager/app/httpclient"
+	"n1kit0s/vt-manager/app/vuetorrent"
+)
+
+const (
+	IndexFileName = "index.json"
+	FormatVersion = 1
+	// maxBundleSize limits extraction of zipped bundles, they hold many release archives.
+	maxBundleSize int64 = 4 * 1024 * 1024 * 1024
+)
+
+// Index describes releases stored in a bundle. Asset download urls are file names inside the bundle.
+type Index struct {
+	FormatVersion int              `json:"format_version"`
+	CreatedAt     time.Time        `json:"created_at"`
+	Repository    string           `json:"repository"`
+	Releases      []github.Release `json:"releases"`
+}
+
+// Client reads releases from a bundle created by Export. It implements github.Client,
+// so a bundle can be used everywhere GitHub is.
+type Client struct {
+	Dir   string
+	index Index
+	// extracted is true if Dir is a temporary extraction of a zipped bundle.
+	extracted bool
+}
+
+// Open reads a bundle directory or a zipped bundle. Zipped bundles are extracted into a new
+// temporary directory, which is removed by Close.
+func Open(ctx context.Context, path string) (*Client, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle. %s", err.Error())
+	}
+
+	if info.IsDir() {
+		return openDir(path, false)
+	}
+
+	dir, err := extractBundle(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	client, err := openDir(dir, true)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return client, nil
+}
+
+// Close removes the extracted zipped bundle. A bundle directory is kept.
+func (c *Client) Close() error {
+	if !c.extracted {
+		return nil
+	}
+	return os.RemoveAll(c.Dir)
+}
+
+func openDir(dir string, extracted bool) (*Client, error) {
+	content, err := os.ReadFile(filepath.Join(dir, IndexFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle index. %s", err.Error())
+	}
+
+	var index Index
+	if err := json.Unmarshal(content, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle index. %s", err.Error())
+	}
+	if index.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d", index.FormatVersion)
+	}
+
+	slog.Debug("Opened bundle", "dir", dir, "releases", len(index.Releases), "createdAt", index.CreatedAt)
+	return &Client{Dir: dir, index: index, extracted: extracted}, nil
+}
+
+// extractBundle unpacks a zipped bundle into a new private temporary directory.
+func extractBundle(ctx context.Context, path string) (string, error) {
+	dir, err := os.MkdirTemp("", "vt-manager-bundle-")
+	if err != nil {
+		return "", fmt.Errorf("failed to extract bundle %s. %s", path, err.Error())
+	}
+
+	unzipper := vuetorrent.DefaultUnzipper{MaxTotalSize: maxBundleSize}
+	if err := unzipper.Unzip(ctx, path, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to extract bundle %s. %s", path, err.Error())
+	}
+
+	return dir, nil
+}
+
+func (c *Client) GetReleases(ctx context.Context) ([]github.Release, error) {
+	var releases = make([]github.Release, 0, len(c.index.Releases))
+	for _, release := range c.index.Releases {
+		releases = append(releases, c.resolveAssets(release))
+	}
+	return releases, nil
+}
+
+func (c *Client) GetReleaseByTag(ctx context.Context, tag string) (github.Release, error) {
+	for _, release := range c.index.Releases {
+		if strings.TrimPrefix(release.TagName, "v") == strings.TrimPrefix(tag, "v") {
+			return c.resolveAssets(release), nil
+		}
+	}
+	return github.Release{}, fmt.Errorf("release %s not found in bundle %s. %w", tag, c.Dir, httpclient.ErrNotFound)
+}
+
+// resolveAssets turns asset file names into file:// urls. Only the base name is used, so an
+// edited index can't point outside of the bundle.
+func (c *Client) resolveAssets(release github.Release) github.Release {
+	var assets = make([]github.Asset, 0, len(release.Assets))
+	for _, asset := range release.Assets {
+		filePath, err := filepath.Abs(filepath.Join(c.Dir, filepath.Base(asset.DownloadUrl)))
+		if err != nil {
+			continue
+		}
+		asset.DownloadUrl = (&url.URL{Scheme: "file", Path: filepath.ToSlash(filePath)}).String()
+		assets = append(assets, asset)
+	}
+	release.Assets = assets
+	return release
+}
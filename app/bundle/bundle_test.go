@@ -0,0 +1,248 @@
+package bundle
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"n1kit0s/vt-manager/app/httpclient"
+	"n1kit0s/vt-manager/app/vuetorrent"
+)
+
+func createReleaseArchive(t *testing.T, version string) []byte {
+	var buffer strings.Builder
+	zipWriter := zip.NewWriter(&buffer)
+	for name, content := range map[string]string{"vuetorrent/version.txt": version, "vuetorrent/public/index.html": "<html></html>"} {
+		writer, _ := zipWriter.Create(name)
+		writer.Write([]byte(content))
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("Can't create release archive. Error: %s", err.Error())
+	}
+	return []byte(buffer.String())
+}
+
+func serveReleases(t *testing.T, versions ...string) (*httptest.Server, []vuetorrent.Release) {
+	var archives = map[string][]byte{}
+	var releases []vuetorrent.Release
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archive, found := archives[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(archive)
+	}))
+
+	for _, version := range versions {
+		path := "/" + version + "/vuetorrent.zip"
+		archives[path] = createReleaseArchive(t, version)
+		releases = append(releases, vuetorrent.Release{
+			Version:     version,
+			DownloadUrl: server.URL + path,
+			AssetName:   "vuetorrent.zip",
+			PublishedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+	}
+
+	return server, releases
+}
+
+func TestExportAndOpen(t *testing.T) {
+	for _, output := range []string{"bundle", "bundle.zip"} {
+		t.Run(output, func(t *testing.T) {
+			// Setup
+			server, releases := serveReleases(t, "2.3.0", "2.2.0")
+			defer server.Close()
+
+			bundlePath := filepath.Join(t.TempDir(), output)
+
+			// Run
+			index, err := Export(context.Background(), releases, vuetorrent.HttpDownloader{}, nil, "VueTorrent/VueTorrent", bundlePath)
+			if err != nil {
+				t.Fatalf("Export failed. Error: %s", err.Error())
+			}
+			if len(index.Releases) != 2 {
+				t.Fatalf("Expected 2 releases in index. Actual: %+v", index.Releases)
+			}
+			server.Close()
+
+			client, err := Open(context.Background(), bundlePath)
+			if err != nil {
+				t.Fatalf("Can't open bundle. Error: %s", err.Error())
+			}
+
+			githubReleases, err := client.GetReleases(context.Background())
+			if err != nil {
+				t.Fatalf("Can't get releases. Error: %s", err.Error())
+			}
+			if len(githubReleases) != 2 || githubReleases[0].TagName != "v2.3.0" {
+				t.Fatalf("Unexpected releases %+v", githubReleases)
+			}
+
+			asset := githubReleases[0].Assets[0]
+			if asset.Name != "vuetorrent.zip" || !strings.HasPrefix(asset.DownloadUrl, "file://") || !strings.HasPrefix(asset.Digest, "sha256:") {
+				t.Fatalf("Unexpected asset %+v", asset)
+			}
+
+			release, err := client.GetReleaseByTag(context.Background(), "2.2.0")
+			if err != nil || release.TagName != "v2.2.0" {
+				t.Fatalf("Can't get release by tag. Release: %+v, error: %v", release, err)
+			}
+			if _, err := client.GetReleaseByTag(context.Background(), "v9.9.9"); !errors.Is(err, httpclient.ErrNotFound) {
+				t.Fatalf("Expected not found error for missing release. Actual: %v", err)
+			}
+
+			if err := client.Close(); err != nil {
+				t.Fatalf("Can't close bundle. Error: %s", err.Error())
+			}
+			// Only the extraction of a zipped bundle is removed
+			if _, err := os.Stat(client.Dir); (err == nil) != (client.Dir == bundlePath) {
+				t.Fatalf("Unexpected bundle directory after close. Error: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstallFromBundle(t *testing.T) {
+	// Setup
+	server, releases := serveReleases(t, "2.3.0", "2.2.0")
+	defer server.Close()
+
+	bundleDir := filepath.Join(t.TempDir(), "bundle")
+	if _, err := Export(context.Background(), releases, vuetorrent.HttpDownloader{}, nil, "VueTorrent/VueTorrent", bundleDir); err != nil {
+		t.Fatalf("Export failed. Error: %s", err.Error())
+	}
+	server.Close()
+
+	client, err := Open(context.Background(), bundleDir)
+	if err != nil {
+		t.Fatalf("Can't open bundle. Error: %s", err.Error())
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+	vtManager := vuetorrent.NewVTManager(vuetorrent.GithubProvider{Client: client}, vuetorrent.Config{})
+
+	// Run
+	result, err := vtManager.Install(context.Background(), "", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	if !result.Changed || result.Version != "2.3.0" {
+		t.Fatalf("Unexpected install result %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "public", "index.html")); err != nil {
+		t.Fatalf("Release was not extracted. Error: %s", err.Error())
+	}
+}
+
+// digestVerifier accepts a signature equal to the sha256 of the file.
+type digestVerifier struct{}
+
+func (v digestVerifier) Extensions() []string {
+	return []string{".minisig"}
+}
+
+func (v digestVerifier) Verify(filePath string, signature []byte) error {
+	content, _ := os.ReadFile(filePath)
+	if sha256Hex(content) != string(signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func sha256Hex(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+func TestInstallSignedReleaseFromBundle(t *testing.T) {
+	// Setup
+	archive := createReleaseArchive(t, "2.3.0")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/vuetorrent.zip":
+			w.Write(archive)
+		case "/vuetorrent.zip.minisig":
+			w.Write([]byte(sha256Hex(archive)))
+		}
+	}))
+	defer server.Close()
+
+	releases := []vuetorrent.Release{{
+		Version:       "2.3.0",
+		DownloadUrl:   server.URL + "/vuetorrent.zip",
+		AssetName:     "vuetorrent.zip",
+		SignatureUrls: []string{server.URL + "/vuetorrent.zip.minisig"},
+	}}
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.zip")
+	if _, err := Export(context.Background(), releases, vuetorrent.HttpDownloader{}, nil, "VueTorrent/VueTorrent", bundlePath); err != nil {
+		t.Fatalf("Export failed. Error: %s", err.Error())
+	}
+	server.Close()
+
+	client, err := Open(context.Background(), bundlePath)
+	if err != nil {
+		t.Fatalf("Can't open bundle. Error: %s", err.Error())
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+	vtManager := vuetorrent.NewVTManager(vuetorrent.GithubProvider{Client: client}, vuetorrent.Config{
+		SignatureVerifier: digestVerifier{},
+		RequireSignature:  true,
+	})
+
+	// Run
+	if _, err := vtManager.Install(context.Background(), "2.3.0", outputDir); err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "public", "index.html")); err != nil {
+		t.Fatalf("Release was not extracted. Error: %s", err.Error())
+	}
+}
+
+func TestOpenRejectsUnknownFormat(t *testing.T) {
+	// Setup
+	bundleDir := t.TempDir()
+	os.WriteFile(filepath.Join(bundleDir, IndexFileName), []byte(`{"format_version": 99}`), 0644)
+
+	// Run
+	if _, err := Open(context.Background(), bundleDir); err == nil {
+		t.Fatal("Expected error for unknown format version")
+	}
+}
+
+func TestResolveAssetsStaysInBundle(t *testing.T) {
+	// Setup
+	bundleDir := t.TempDir()
+	os.WriteFile(filepath.Join(bundleDir, IndexFileName), []byte(`{"format_version": 1, "releases": [
+		{"tag_name": "v2.3.0", "assets": [{"name": "vuetorrent.zip", "browser_download_url": "../../etc/passwd"}]}
+	]}`), 0644)
+
+	client, err := Open(context.Background(), bundleDir)
+	if err != nil {
+		t.Fatalf("Can't open bundle. Error: %s", err.Error())
+	}
+
+	// Run
+	release, _ := client.GetReleaseByTag(context.Background(), "v2.3.0")
+
+	expectedUrl := "file://" + filepath.ToSlash(filepath.Join(bundleDir, "passwd"))
+	if release.Assets[0].DownloadUrl != expectedUrl {
+		t.Fatalf("Asset url escapes bundle. Expected %s | Actual %s", expectedUrl, release.Assets[0].DownloadUrl)
+	}
+}
@@ -0,0 +1,204 @@
+package bundle
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"n1kit0s/vt-manager/app/github"
+	"n1kit0s/vt-manager/app/vuetorrent"
+)
+
+// Export downloads the releases with their signature assets and writes them with an index into output.
+// If output ends with .zip, the bundle is written as a single zip archive, otherwise into a directory.
+// If ctx is cancelled, a partially written zip archive is removed. client downloads signatures,
+// nil means http.DefaultClient.
+func Export(ctx context.Context, releases []vuetorrent.Release, downloader vuetorrent.Downloader, client *http.Client, repository string, output string) (Index, error) {
+	if len(releases) == 0 {
+		return Index{}, fmt.Errorf("no releases to export")
+	}
+
+	var dir = output
+	var zipped = strings.EqualFold(filepath.Ext(output), ".zip")
+	if zipped {
+		tempDir, err := os.MkdirTemp("", "vt-manager-bundle-")
+		if err != nil {
+			return Index{}, err
+		}
+		defer os.RemoveAll(tempDir)
+		dir = tempDir
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return Index{}, err
+	}
+
+	var index = Index{FormatVersion: FormatVersion, CreatedAt: time.Now().UTC(), Repository: repository}
+	for _, release := range releases {
+		if release.DownloadUrl == "" {
+			slog.Warn("Release has no matching asset. Skipping", "version", release.Version)
+			continue
+		}
+
+		slog.Info("Exporting release", "version", release.Version)
+		filePath, err := downloader.Download(ctx, release, dir)
+		if err != nil {
+			return Index{}, fmt.Errorf("failed to download release %s. %s", release.Version, err.Error())
+		}
+
+		asset, err := bundleAsset(filePath, release.AssetName)
+		if err != nil {
+			return Index{}, err
+		}
+
+		var assets = []github.Asset{asset}
+		for _, signatureUrl := range release.SignatureUrls {
+			signatureAsset, err := exportSignature(ctx, signatureUrl, client, filePath, release.AssetName)
+			if err != nil {
+				return Index{}, fmt.Errorf("failed to export signature of release %s. %s", release.Version, err.Error())
+			}
+			assets = append(assets, signatureAsset)
+		}
+
+		index.Releases = append(index.Releases, github.Release{
+			TagName:     vuetorrent.MakeTagName(release.Version),
+			Name:        vuetorrent.MakeTagName(release.Version),
+			Prerelease:  release.Prerelease,
+			PublishedAt: release.PublishedAt,
+			Assets:      assets,
+		})
+	}
+
+	if err := writeIndex(index, dir); err != nil {
+		return Index{}, err
+	}
+
+	if zipped {
+		if err := zipDir(ctx, dir, output); err != nil {
+			os.Remove(output)
+			return Index{}, fmt.Errorf("failed to write bundle %s. %s", output, err.Error())
+		}
+	}
+
+	return index, nil
+}
+
+// bundleAsset describes an archive stored in the bundle. Its digest allows verification on the offline host.
+func bundleAsset(filePath string, assetName string) (github.Asset, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return github.Asset{}, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return github.Asset{}, err
+	}
+
+	return github.Asset{
+		Name:        assetName,
+		DownloadUrl: filepath.Base(filePath),
+		Size:        size,
+		Digest:      "sha256:" + hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// exportSignature stores the signature next to the archive as <archive file><extension>. The asset
+// keeps the name <asset name><extension>, so it's matched to the archive on the offline host.
+func exportSignature(ctx context.Context, signatureUrl string, client *http.Client, archivePath string, assetName string) (github.Asset, error) {
+	signature, err := vuetorrent.DownloadSignature(ctx, signatureUrl, client)
+	if err != nil {
+		return github.Asset{}, err
+	}
+
+	extension := vuetorrent.SignatureExtension(signatureUrl)
+	filePath := archivePath + extension
+	if err := os.WriteFile(filePath, signature, 0644); err != nil {
+		return github.Asset{}, err
+	}
+
+	return github.Asset{
+		Name:        assetName + extension,
+		DownloadUrl: filepath.Base(filePath),
+		Size:        int64(len(signature)),
+	}, nil
+}
+
+func writeIndex(index Index, dir string) error {
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, IndexFileName), content, 0644)
+}
+
+// zipDir writes regular files from the top level of dir into a zip archive.
+func zipDir(ctx context.Context, dir string, output string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), os.ModePerm); err != nil {
+		return err
+	}
+	archive, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	zipWriter := zip.NewWriter(archive)
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := addZipEntry(zipWriter, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+func addZipEntry(zipWriter *zip.Writer, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	// Release archives are already compressed
+	header.Method = zip.Store
+
+	writer, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, file)
+	return err
+}
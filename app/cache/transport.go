@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Subdirectories of the cache directory
+const (
+	// ResponsesDir keeps API responses cached by Transport.
+	ResponsesDir = "api"
+	// ArchivesDir keeps downloaded release archives.
+	ArchivesDir = "archives"
+)
+
+// DefaultDir returns the per-user cache directory of vt-manager, e.g. ~/.cache/vt-manager.
+func DefaultDir() (string, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory. %s", err.Error())
+	}
+	return filepath.Join(userCacheDir, "vt-manager"), nil
+}
+
+// Clear removes cached API responses and archives from the cache directory.
+func Clear(dir string) error {
+	for _, subdir := range []string{ResponsesDir, ArchivesDir} {
+		if err := os.RemoveAll(filepath.Join(dir, subdir)); err != nil {
+			return fmt.Errorf("failed to clear cache [%s]. %s", dir, err.Error())
+		}
+	}
+	return nil
+}
+
+type entry struct {
+	Url          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	StoredAt     time.Time   `json:"stored_at"`
+}
+
+// Transport caches successful GET responses on disk, keyed by url. A cached response is
+// revalidated with If-None-Match/If-Modified-Since on every request and reused if the
+// server answers 304 Not Modified. Responses without ETag and Last-Modified are not cached.
+type Transport struct {
+	// Dir is the directory with cached responses.
+	Dir string
+	// Base executes the requests. Nil means http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// NewTransport returns a Transport storing responses in the ResponsesDir of cacheDir.
+func NewTransport(cacheDir string, base http.RoundTripper) *Transport {
+	return &Transport{Dir: filepath.Join(cacheDir, ResponsesDir), Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base().RoundTrip(req)
+	}
+
+	var key = req.URL.String()
+	cached, found := t.load(key)
+	if found {
+		// RoundTrip must not modify the original request
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		slog.Debug("Cached response is up to date", "url", key)
+		return cached.response(req, resp.Header), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || (resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = t.store(entry{
+		Url:          key,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       resp.Header,
+		Body:         body,
+		StoredAt:     time.Now(),
+	})
+	if err != nil {
+		// The response is still usable, it will be requested in full next time
+		slog.Warn("Failed to cache response", "url", key, "error", err.Error())
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+func (t *Transport) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (t *Transport) load(key string) (entry, bool) {
+	content, err := os.ReadFile(t.path(key))
+	if err != nil {
+		return entry{}, false
+	}
+
+	var cached entry
+	if err := json.Unmarshal(content, &cached); err != nil || cached.Url != key {
+		slog.Debug("Ignoring invalid cache entry", "url", key)
+		return entry{}, false
+	}
+
+	return cached, true
+}
+
+// store writes the entry to a temporary file first, so concurrent readers never see a partial entry.
+func (t *Transport) store(cached entry) error {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(t.Dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), t.path(cached.Url))
+}
+
+// response rebuilds the cached response. Headers of the 304 response (e.g. rate limit) replace the cached ones.
+func (cached entry) response(req *http.Request, notModifiedHeader http.Header) *http.Response {
+	var header = cached.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for name, values := range notModifiedHeader {
+		header[name] = values
+	}
+	header.Set("Content-Length", fmt.Sprint(len(cached.Body)))
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func getBody(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("request failed. %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body. %s", err.Error())
+	}
+	return resp, string(body)
+}
+
+func TestTransportRevalidatesWithETag(t *testing.T) {
+	// Setup
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<https://example.com?page=2>; rel="next"`)
+		w.Write([]byte(`[{"tag_name":"v2.3.0"}]`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(t.TempDir(), nil)}
+
+	// Run
+	_, firstBody := getBody(t, client, server.URL)
+	resp, secondBody := getBody(t, client, server.URL)
+
+	if len(requests) != 2 || requests[1].Header.Get("If-None-Match") != `"v1"` {
+		t.Fatalf("Cached response was not revalidated")
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200 for a cache hit. Actual: %d", resp.StatusCode)
+	}
+	if secondBody != firstBody {
+		t.Errorf("Unexpected cached body: %s", secondBody)
+	}
+	if resp.Header.Get("Link") == "" || resp.Header.Get("X-RateLimit-Remaining") != "59" {
+		t.Errorf("Headers were not restored. Header: %v", resp.Header)
+	}
+}
+
+func TestTransportRevalidatesWithLastModified(t *testing.T) {
+	// Setup
+	const lastModified = "Wed, 29 Nov 2023 07:28:55 GMT"
+	var ifModifiedSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifModifiedSince = r.Header.Get("If-Modified-Since")
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(t.TempDir(), nil)}
+
+	// Run
+	getBody(t, client, server.URL)
+	_, body := getBody(t, client, server.URL)
+
+	if ifModifiedSince != lastModified {
+		t.Errorf("If-Modified-Since was not sent. Actual: [%s]", ifModifiedSince)
+	}
+	if body != "content" {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
+
+func TestTransportReplacesChangedResponse(t *testing.T) {
+	// Setup
+	var version = "v1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == version {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", version)
+		w.Write([]byte(version))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(t.TempDir(), nil)}
+
+	// Run
+	getBody(t, client, server.URL)
+	version = "v2"
+	_, changedBody := getBody(t, client, server.URL)
+	_, cachedBody := getBody(t, client, server.URL)
+
+	if changedBody != "v2" || cachedBody != "v2" {
+		t.Errorf("Stale response was used. Bodies: %s, %s", changedBody, cachedBody)
+	}
+}
+
+func TestTransportSkipsUncacheableResponses(t *testing.T) {
+	tests := map[string]http.HandlerFunc{
+		"no validators": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("content"))
+		},
+		"error status": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			w.WriteHeader(http.StatusNotFound)
+		},
+	}
+
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			cacheDir := t.TempDir()
+			client := &http.Client{Transport: NewTransport(cacheDir, nil)}
+
+			// Run
+			getBody(t, client, server.URL)
+
+			entries, _ := os.ReadDir(filepath.Join(cacheDir, ResponsesDir))
+			if len(entries) != 0 {
+				t.Errorf("Response was cached")
+			}
+		})
+	}
+}
+
+func TestClear(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	client := &http.Client{Transport: NewTransport(cacheDir, nil)}
+	getBody(t, client, server.URL)
+
+	// Run
+	if err := Clear(cacheDir); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := os.Stat(filepath.Join(cacheDir, ResponsesDir)); !os.IsNotExist(err) {
+		t.Errorf("Cached responses were not removed")
+	}
+}
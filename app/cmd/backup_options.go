@@ -0,0 +1,12 @@
+package cmd
+
+import "n1kit0s/vt-manager/app/vuetorrent"
+
+type BackupOptions struct {
+	BackupDir   string `long:"backup-dir" description:"Directory for previous VueTorrent versions (default: .<dir>-backups next to VueTorrent directory)" env:"VUETORRENT_BACKUP_DIRECTORY"`
+	KeepBackups int    `long:"keep-backups" default:"3" description:"Number of previous versions to keep" env:"VUETORRENT_KEEP_BACKUPS"`
+}
+
+func (o BackupOptions) newBackupStore(installDir string) vuetorrent.BackupStore {
+	return vuetorrent.NewBackupStore(installDir, o.BackupDir, o.KeepBackups)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+type BackupsCommand struct {
+	Directory string `short:"d" long:"dir" required:"true" description:"VueTorrent directory" env:"VUETORRENT_DIRECTORY"`
+	BackupDir string `long:"backup-dir" description:"Directory for previous VueTorrent versions (default: .<dir>-backups next to VueTorrent directory)" env:"VUETORRENT_BACKUP_DIRECTORY"`
+
+	globalCommand
+}
+
+func (c *BackupsCommand) Execute(args []string) error {
+	var backups = BackupOptions{BackupDir: c.BackupDir}.newBackupStore(c.Directory)
+
+	list, err := backups.List()
+	if err != nil {
+		return err
+	}
+
+	var result = make([]backupResult, 0, len(list))
+	for _, backup := range list {
+		result = append(result, backupResult{Version: backup.Version, CreatedAt: backup.CreatedAt, Size: backup.Size, Path: backup.Path})
+	}
+
+	return c.global.printResult(result, func() {
+		for _, backup := range list {
+			fmt.Printf("%s\t%s\t%s\t%s\n", backup.Version, backup.CreatedAt.Format(time.DateTime), formatSize(backup.Size), backup.Path)
+		}
+	})
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"n1kit0s/vt-manager/app/bundle"
+	"n1kit0s/vt-manager/app/vuetorrent"
+)
+
+type BundleCommand struct {
+	ExportCmd BundleExportCommand `command:"export" description:"Download releases into a bundle for hosts without internet access"`
+}
+
+type BundleExportCommand struct {
+	Version     string `short:"v" long:"version" description:"Version constraint of exported releases (default: all releases)" env:"VUETORRENT_INSTALL_VERSION"`
+	Channel     string `short:"c" long:"channel" default:"stable" choice:"stable" choice:"prerelease" description:"Release channel" env:"VUETORRENT_CHANNEL"`
+	Destination string `long:"dest" required:"true" description:"Bundle directory, or zip file if it ends with .zip"`
+
+	SourceOptions   `group:"Release source options"`
+	DownloadOptions `group:"Download options"`
+
+	globalCommand
+}
+
+func (c *BundleExportCommand) Execute(args []string) error {
+	ctx, stop := signalContext()
+	defer stop()
+
+	assetPattern, err := c.assetPattern()
+	if err != nil {
+		return err
+	}
+
+	provider, closeProvider, err := c.newProvider(ctx, c.global)
+	if err != nil {
+		return err
+	}
+	defer closeProvider()
+
+	var vtManager = vuetorrent.NewVTManager(provider, vuetorrent.Config{
+		Channel:      vuetorrent.Channel(c.Channel),
+		AssetPattern: assetPattern,
+	})
+
+	releases, err := vtManager.GetReleasesForConstraint(ctx, c.Version)
+	if err != nil {
+		return err
+	}
+
+	var downloader = vuetorrent.HttpDownloader{Client: c.global.httpClient(), OnProgress: newProgressReporter(), LimitRate: c.limitRate()}
+	index, err := bundle.Export(ctx, releases, downloader, downloader.Client, c.Repository, c.Destination)
+	if err != nil {
+		return err
+	}
+
+	var result = bundleResult{Path: c.Destination, Versions: make([]string, 0, len(index.Releases))}
+	for _, release := range index.Releases {
+		result.Versions = append(result.Versions, release.TagName)
+	}
+
+	return c.global.printResult(result, func() {
+		fmt.Printf("Exported %d releases into %s\n", len(result.Versions), result.Path)
+	})
+}
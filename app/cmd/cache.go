@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"n1kit0s/vt-manager/app/cache"
+	"n1kit0s/vt-manager/app/vuetorrent"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+type CacheOptions struct {
+	Dir          string `long:"cache-dir" description:"Directory for cached API responses and archives (default: ~/.cache/vt-manager)" env:"VT_MANAGER_CACHE_DIR"`
+	Disabled     bool   `long:"no-cache" description:"Don't use cached API responses and archives, downloaded archives are removed after install" env:"VT_MANAGER_NO_CACHE"`
+	KeepArchives int    `long:"cache-keep" default:"3" description:"Number of most recently used archives kept in the cache (0 - no limit)" env:"VT_MANAGER_CACHE_KEEP"`
+	MaxSize      int64  `long:"cache-max-size" default:"0" description:"Maximum total size of cached archives in MiB (0 - no limit)" env:"VT_MANAGER_CACHE_MAX_SIZE"`
+}
+
+func (o CacheOptions) resolveDir() (string, error) {
+	if o.Dir != "" {
+		return o.Dir, nil
+	}
+	return cache.DefaultDir()
+}
+
+// apiTransport returns the transport for release source API requests. Responses are cached
+// unless --no-cache is set. If the cache directory can't be found requests are not cached.
+func (o *GlobalOptions) apiTransport() http.RoundTripper {
+	if o.Cache.Disabled {
+		return o.httpTransport()
+	}
+
+	dir, err := o.Cache.resolveDir()
+	if err != nil {
+		slog.Warn("API responses won't be cached", "error", err.Error())
+		return o.httpTransport()
+	}
+
+	return cache.NewTransport(dir, o.httpTransport())
+}
+
+// archiveCache returns nil if downloaded archives shouldn't be kept.
+func (o *GlobalOptions) archiveCache() *vuetorrent.ArchiveCache {
+	if o.Cache.Disabled {
+		return nil
+	}
+
+	dir, err := o.Cache.resolveDir()
+	if err != nil {
+		slog.Warn("Archives won't be cached", "error", err.Error())
+		return nil
+	}
+
+	return o.newArchiveCache(dir)
+}
+
+// partialDownloadsDir keeps unfinished downloads with --no-cache, so they are resumed like cached ones.
+// It's empty if the cache directory can't be found.
+func (o *GlobalOptions) partialDownloadsDir() string {
+	dir, err := o.Cache.resolveDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, cache.ArchivesDir, vuetorrent.PartialDownloadsDir)
+}
+
+func (o *GlobalOptions) newArchiveCache(dir string) *vuetorrent.ArchiveCache {
+	return &vuetorrent.ArchiveCache{
+		Dir:     filepath.Join(dir, cache.ArchivesDir),
+		Keep:    o.Cache.KeepArchives,
+		MaxSize: o.Cache.MaxSize * 1024 * 1024,
+		Client:  o.httpClient(),
+	}
+}
+
+type CacheCommand struct {
+	ListCmd  CacheListCommand  `command:"list" description:"Print cached archives"`
+	PruneCmd CachePruneCommand `command:"prune" description:"Remove archives exceeding --cache-keep and --cache-max-size"`
+	ClearCmd CacheClearCommand `command:"clear" description:"Remove cached API responses and archives"`
+}
+
+type CacheListCommand struct {
+	globalCommand
+}
+
+func (c *CacheListCommand) Execute(args []string) error {
+	dir, err := c.global.Cache.resolveDir()
+	if err != nil {
+		return err
+	}
+
+	archives, err := c.global.newArchiveCache(dir).List()
+	if err != nil {
+		return err
+	}
+
+	return printArchives(c.global, archives, func() {
+		for _, archive := range archives {
+			fmt.Printf("%s\t%s\t%s\t%s\n", archive.Version, archive.UsedAt.Format(time.DateTime), formatSize(archive.Size), archive.Path)
+		}
+	})
+}
+
+type CachePruneCommand struct {
+	globalCommand
+}
+
+func (c *CachePruneCommand) Execute(args []string) error {
+	dir, err := c.global.Cache.resolveDir()
+	if err != nil {
+		return err
+	}
+
+	removed, err := c.global.newArchiveCache(dir).Prune()
+	if err != nil {
+		return err
+	}
+
+	return printArchives(c.global, removed, func() {
+		var freed int64
+		for _, archive := range removed {
+			freed += archive.Size
+		}
+		slog.Info(fmt.Sprintf("Removed %d archives, freed %s", len(removed), formatSize(freed)))
+	})
+}
+
+func printArchives(global *GlobalOptions, archives []vuetorrent.CachedArchive, printText func()) error {
+	var result = make([]cachedArchiveResult, 0, len(archives))
+	for _, archive := range archives {
+		result = append(result, cachedArchiveResult{
+			Version: archive.Version,
+			Digest:  archive.Digest.String(),
+			Size:    archive.Size,
+			UsedAt:  archive.UsedAt,
+			Path:    archive.Path,
+		})
+	}
+
+	return global.printResult(result, printText)
+}
+
+type CacheClearCommand struct {
+	globalCommand
+}
+
+func (c *CacheClearCommand) Execute(args []string) error {
+	dir, err := c.global.Cache.resolveDir()
+	if err != nil {
+		return err
+	}
+
+	if err := cache.Clear(dir); err != nil {
+		return err
+	}
+
+	return c.global.printResult(cacheClearResult{Dir: dir}, func() {
+		slog.Info("Cache cleared", "dir", dir)
+	})
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"n1kit0s/vt-manager/app/config"
+	"n1kit0s/vt-manager/app/vuetorrent"
+)
+
+// Exit codes of the check command. Errors exit with 1 like every other command.
+const (
+	ExitUpToDate        = 0
+	ExitUpdateAvailable = 2
+	ExitNotInstalled    = 3
+	ExitNewerInstalled  = 4
+)
+
+// ExitCodeError makes the process exit with Code without reporting an error.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.Code)
+}
+
+type CheckCommand struct {
+	Version   string `short:"v" long:"version" optional:"true" description:"VueTorrent version or version constraint to check against (default: latest)" env:"VUETORRENT_INSTALL_VERSION"`
+	Directory string `short:"d" long:"dir" description:"VueTorrent directory (required if no instances are configured)" env:"VUETORRENT_DIRECTORY"`
+	Channel   string `short:"c" long:"channel" choice:"stable" choice:"prerelease" description:"Release channel (default: stable)" env:"VUETORRENT_CHANNEL"`
+
+	InstanceOptions
+	SourceOptions `group:"Release source options"`
+
+	globalCommand
+}
+
+// Execute checks every selected instance. The exit code is the highest one of all instances.
+func (c *CheckCommand) Execute(args []string) error {
+	instances, err := c.instances(c.global.Config, config.Instance{Directory: c.Directory, Version: c.Version, Channel: c.Channel})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	var results []checkResult
+	var exitCode = ExitUpToDate
+	err = forEachInstance(instances, func(instance config.Instance) error {
+		check, err := c.check(ctx, instance)
+		if err != nil {
+			return err
+		}
+
+		results = append(results, checkResult{
+			Instance:         instance.Name,
+			InstalledVersion: check.InstalledVersion,
+			AvailableVersion: check.AvailableVersion,
+			Status:           string(check.Status),
+		})
+		exitCode = max(exitCode, checkExitCode(check.Status))
+		return nil
+	})
+
+	printErr := printInstanceResults(c.global, instances, results, func() {
+		for _, result := range results {
+			if result.Instance != "" {
+				fmt.Printf("%s: ", result.Instance)
+			}
+			switch vuetorrent.UpdateStatus(result.Status) {
+			case vuetorrent.StatusUpToDate:
+				fmt.Printf("%s is up to date\n", result.InstalledVersion)
+			case vuetorrent.StatusNotInstalled:
+				fmt.Printf("not installed -> %s\n", result.AvailableVersion)
+			case vuetorrent.StatusNewerInstalled:
+				fmt.Printf("%s is newer than %s\n", result.InstalledVersion, result.AvailableVersion)
+			default:
+				fmt.Printf("%s -> %s\n", result.InstalledVersion, result.AvailableVersion)
+			}
+		}
+	})
+	if printErr != nil {
+		return printErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if exitCode != ExitUpToDate {
+		return &ExitCodeError{Code: exitCode}
+	}
+	return nil
+}
+
+func (c *CheckCommand) check(ctx context.Context, instance config.Instance) (vuetorrent.CheckResult, error) {
+	assetPattern, err := c.assetPattern()
+	if err != nil {
+		return vuetorrent.CheckResult{}, err
+	}
+
+	provider, closeProvider, err := c.newProvider(ctx, c.global)
+	if err != nil {
+		return vuetorrent.CheckResult{}, err
+	}
+	defer closeProvider()
+
+	var vtManager = vuetorrent.NewVTManager(provider, vuetorrent.Config{
+		Channel:      vuetorrent.Channel(instance.Channel),
+		AssetPattern: assetPattern,
+	})
+
+	return vtManager.Check(ctx, instance.Version, instance.Directory)
+}
+
+func checkExitCode(status vuetorrent.UpdateStatus) int {
+	switch status {
+	case vuetorrent.StatusUpdateAvailable:
+		return ExitUpdateAvailable
+	case vuetorrent.StatusNotInstalled:
+		return ExitNotInstalled
+	case vuetorrent.StatusNewerInstalled:
+		return ExitNewerInstalled
+	}
+	return ExitUpToDate
+}
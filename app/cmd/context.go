@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// signalContext returns a context cancelled on Ctrl-C or SIGTERM. In-flight requests, downloads
+// and extraction are stopped and their temporary files removed.
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
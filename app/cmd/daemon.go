@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"n1kit0s/vt-manager/app/schedule"
+	"time"
+)
+
+type DaemonCommand struct {
+	Interval       time.Duration `long:"interval" default:"6h" description:"Time between update checks" env:"VT_MANAGER_INTERVAL"`
+	Schedule       string        `long:"schedule" description:"Cron expression for update checks, overrides --interval (e.g. \"0 4 * * *\", @daily)" env:"VT_MANAGER_SCHEDULE"`
+	SkipFirstCheck bool          `long:"skip-first-check" description:"Wait for the first scheduled check instead of checking on start" env:"VT_MANAGER_SKIP_FIRST_CHECK"`
+	RetryDelay     time.Duration `long:"retry-delay" default:"1m" description:"Delay before the first retry of a failed check" env:"VT_MANAGER_RETRY_DELAY"`
+	MaxRetryDelay  time.Duration `long:"max-retry-delay" default:"1h" description:"Maximum delay between retries of a failed check" env:"VT_MANAGER_MAX_RETRY_DELAY"`
+
+	InstallCommand
+}
+
+func (c *DaemonCommand) Execute(args []string) error {
+	checkSchedule, err := c.schedule()
+	if err != nil {
+		return err
+	}
+
+	if c.RetryDelay <= 0 {
+		return fmt.Errorf("retry delay must be positive. got %s", c.RetryDelay)
+	}
+
+	// Invalid signature options must fail before anything is downloaded
+	if _, err := c.signatureVerifier(); err != nil {
+		return err
+	}
+
+	instances, err := c.instances(c.global.Config, c.overrides())
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	runner := schedule.Runner{
+		Schedule:   checkSchedule,
+		Backoff:    schedule.Backoff{Initial: c.RetryDelay, Max: c.MaxRetryDelay},
+		RunOnStart: !c.SkipFirstCheck,
+	}
+
+	for _, instance := range instances {
+		slog.Info("Starting daemon", "instance", instance.Name, "dir", instance.Directory, "version", instance.Version, "channel", instance.Channel)
+	}
+	return runner.Run(ctx, func(ctx context.Context) error {
+		_, err := c.installAll(ctx, instances)
+		return err
+	})
+}
+
+func (c *DaemonCommand) schedule() (schedule.Schedule, error) {
+	if c.Schedule != "" {
+		return schedule.Parse(c.Schedule)
+	}
+
+	if c.Interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive. got %s", c.Interval)
+	}
+	return schedule.Interval(c.Interval), nil
+}
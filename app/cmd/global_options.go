@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"net/http"
+)
+
+// GlobalOptions are accepted by every command. The command handler passes them to the executed command.
+type GlobalOptions struct {
+	Debug  bool   `long:"debug" description:"Enable debug logging" env:"VT_MANAGER_DEBUG"`
+	Config string `long:"config" description:"Config file with VueTorrent instances (default: first of ./vt-manager.yaml, ~/.config/vt-manager/config.yaml, /etc/vt-manager/config.yaml)" env:"VT_MANAGER_CONFIG"`
+	Output string `short:"o" long:"output" default:"text" choice:"text" choice:"json" choice:"yaml" description:"Output format of command results" env:"VT_MANAGER_OUTPUT"`
+
+	Cache CacheOptions `group:"Cache options"`
+	Http  HttpOptions  `group:"HTTP options"`
+
+	// transport is shared by API requests and downloads. Nil means http.DefaultTransport.
+	transport http.RoundTripper
+}
+
+// Command is a command using the global options.
+type Command interface {
+	Execute(args []string) error
+	SetGlobalOptions(options *GlobalOptions) error
+}
+
+// globalCommand is embedded into commands to receive the global options.
+type globalCommand struct {
+	global *GlobalOptions
+}
+
+// SetGlobalOptions fails if the proxy url, CA file or client certificate is invalid.
+func (c *globalCommand) SetGlobalOptions(options *GlobalOptions) error {
+	transport, err := options.Http.newTransport()
+	if err != nil {
+		return err
+	}
+
+	var global = *options
+	global.transport = transport
+	c.global = &global
+	return nil
+}
+
+// Commands without the global options would dereference a nil pointer
+var (
+	_ Command = (*InstallCommand)(nil)
+	_ Command = (*DaemonCommand)(nil)
+	_ Command = (*InfoCommand)(nil)
+	_ Command = (*ListCommand)(nil)
+	_ Command = (*CheckCommand)(nil)
+	_ Command = (*RevisionCommand)(nil)
+	_ Command = (*RollbackCommand)(nil)
+	_ Command = (*BackupsCommand)(nil)
+	_ Command = (*QbittorrentConfigureCommand)(nil)
+	_ Command = (*BundleExportCommand)(nil)
+	_ Command = (*CacheListCommand)(nil)
+	_ Command = (*CachePruneCommand)(nil)
+	_ Command = (*CacheClearCommand)(nil)
+)
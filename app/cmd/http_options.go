@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
+	"time"
+)
+
+type HttpOptions struct {
+	ConnectTimeout     time.Duration `long:"connect-timeout" default:"10s" description:"Timeout for establishing a connection" env:"VT_MANAGER_CONNECT_TIMEOUT"`
+	ReadTimeout        time.Duration `long:"read-timeout" default:"30s" description:"Timeout for waiting on response data" env:"VT_MANAGER_READ_TIMEOUT"`
+	Retries            int           `long:"http-retries" default:"3" description:"Number of retries of requests failed with a network or server error" env:"VT_MANAGER_HTTP_RETRIES"`
+	RetryDelay         time.Duration `long:"http-retry-delay" default:"1s" description:"Delay before the first retry of a request, doubled for every next one" env:"VT_MANAGER_HTTP_RETRY_DELAY"`
+	Proxy              string        `long:"proxy" description:"Proxy url (http, https or socks5) for API requests and downloads. Hosts in NO_PROXY are reached directly (default: HTTPS_PROXY or HTTP_PROXY)" env:"VT_MANAGER_PROXY"`
+	CAFile             string        `long:"ca-file" description:"PEM file with CA certificates trusted in addition to the system ones" env:"VT_MANAGER_CA_FILE"`
+	ClientCert         string        `long:"client-cert" description:"PEM client certificate for mutual TLS" env:"VT_MANAGER_CLIENT_CERT"`
+	ClientKey          string        `long:"client-key" description:"PEM private key of the client certificate" env:"VT_MANAGER_CLIENT_KEY"`
+	InsecureSkipVerify bool          `long:"insecure-skip-verify" description:"Don't verify server certificates. Use only for testing" env:"VT_MANAGER_INSECURE_SKIP_VERIFY"`
+}
+
+func (o HttpOptions) newTransport() (http.RoundTripper, error) {
+	return httpclient.NewTransport(httpclient.Config{
+		ConnectTimeout:     o.ConnectTimeout,
+		ReadTimeout:        o.ReadTimeout,
+		Retries:            o.Retries,
+		RetryDelay:         o.RetryDelay,
+		Proxy:              o.Proxy,
+		CAFile:             o.CAFile,
+		CertFile:           o.ClientCert,
+		KeyFile:            o.ClientKey,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	})
+}
+
+func (o *GlobalOptions) httpTransport() http.RoundTripper {
+	if o.transport == nil {
+		return http.DefaultTransport
+	}
+	return o.transport
+}
+
+func (o *GlobalOptions) httpClient() *http.Client {
+	return &http.Client{Transport: o.httpTransport()}
+}
@@ -3,19 +3,46 @@ package cmd
 import (
 	"fmt"
 	"log/slog"
+	"n1kit0s/vt-manager/app/config"
 	"n1kit0s/vt-manager/app/vuetorrent"
 )
 
 type InfoCommand struct {
-	Directory string `short:"d" long:"dir" required:"true" description:"VueTorrent directory" env:"VUETORRENT_DIRECTORY"`
+	Directory string `short:"d" long:"dir" description:"VueTorrent directory (required if no instances are configured)" env:"VUETORRENT_DIRECTORY"`
+
+	InstanceOptions
+
+	globalCommand
 }
 
 func (c *InfoCommand) Execute(args []string) error {
-	version, err := vuetorrent.GetInstalledVersion(c.Directory)
+	instances, err := c.instances(c.global.Config, config.Instance{Directory: c.Directory})
 	if err != nil {
 		return err
 	}
 
-	slog.Info(fmt.Sprintf("Version: %s", version))
-	return nil
+	var results []infoResult
+	err = forEachInstance(instances, func(instance config.Instance) error {
+		info, err := vuetorrent.GetInstallInfo(instance.Directory)
+		if err != nil {
+			return err
+		}
+		results = append(results, infoResult{Instance: instance.Name, Version: info.Version, Path: info.Directory, InstalledAt: info.InstalledAt})
+		return nil
+	})
+
+	printErr := printInstanceResults(c.global, instances, results, func() {
+		for _, result := range results {
+			if result.Instance == "" {
+				slog.Info(fmt.Sprintf("Version: %s", result.Version))
+			} else {
+				slog.Info(fmt.Sprintf("Version: %s", result.Version), "instance", result.Instance)
+			}
+		}
+	})
+	if printErr != nil {
+		return printErr
+	}
+
+	return err
 }
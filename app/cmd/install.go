@@ -1,24 +1,147 @@
 package cmd
 
 import (
-	"n1kit0s/vt-manager/app/github"
+	"context"
+	"errors"
+	"n1kit0s/vt-manager/app/config"
 	"n1kit0s/vt-manager/app/vuetorrent"
 )
 
 type InstallCommand struct {
-	Version      string `short:"v" long:"version" optional:"true" description:"VueTorrent version to install" env:"VUETORRENT_INSTALL_VERSION"`
-	Directory    string `short:"d" long:"dir" required:"true" description:"VueTorrent directory" env:"VUETORRENT_DIRECTORY"`
-	GithubApiKey string `short:"k" long:"api-key" required:"true" description:"Github API key" env:"GITHUB_API_KEY"`
+	Version   string `short:"v" long:"version" optional:"true" description:"VueTorrent version or version constraint to install (e.g. 2.3.0, ~2.3, ^2, 2.x). Exact version of --from-file/--from-url archive" env:"VUETORRENT_INSTALL_VERSION"`
+	Directory string `short:"d" long:"dir" description:"VueTorrent directory (required if no instances are configured)" env:"VUETORRENT_DIRECTORY"`
+	Channel   string `short:"c" long:"channel" choice:"stable" choice:"prerelease" description:"Release channel (default: stable)" env:"VUETORRENT_CHANNEL"`
+	FromFile  string `long:"from-file" description:"Install a local VueTorrent archive instead of a GitHub release" env:"VUETORRENT_FROM_FILE"`
+	FromUrl   string `long:"from-url" description:"Download VueTorrent archive from url instead of GitHub" env:"VUETORRENT_FROM_URL"`
+
+	MaxExtractSize  int64 `long:"max-extract-mb" default:"200" description:"Maximum uncompressed size of the release archive in MiB" env:"VUETORRENT_MAX_EXTRACT_MB"`
+	MaxExtractFiles int   `long:"max-extract-files" default:"10000" description:"Maximum number of entries in the release archive" env:"VUETORRENT_MAX_EXTRACT_FILES"`
+
+	InstanceOptions
+	SourceOptions      `group:"Release source options"`
+	BackupOptions      `group:"Backup options"`
+	DownloadOptions    `group:"Download options"`
+	SignatureOptions   `group:"Signature options"`
+	QbittorrentOptions `group:"qBittorrent options"`
+
+	globalCommand
 }
 
 func (c *InstallCommand) Execute(args []string) error {
-	var githubClient = github.NewClient(c.GithubApiKey)
-	var vtManager = vuetorrent.NewVTManager(githubClient)
+	if c.FromFile != "" && c.FromUrl != "" {
+		return errors.New("--from-file and --from-url can't be used together")
+	}
+
+	// Invalid signature options must fail before anything is downloaded
+	if _, err := c.signatureVerifier(); err != nil {
+		return err
+	}
 
-	err := vtManager.Install(c.Version, c.Directory)
+	instances, err := c.instances(c.global.Config, c.overrides())
 	if err != nil {
 		return err
 	}
 
-	return nil
+	ctx, stop := signalContext()
+	defer stop()
+
+	results, err := c.installAll(ctx, instances)
+	if printErr := printInstanceResults(c.global, instances, results, nil); printErr != nil {
+		return printErr
+	}
+
+	return err
+}
+
+// overrides returns values from flags and env vars that replace values from the config file.
+func (c *InstallCommand) overrides() config.Instance {
+	return config.Instance{
+		Directory:   c.Directory,
+		Version:     c.Version,
+		Channel:     c.Channel,
+		BackupDir:   c.BackupDir,
+		Qbittorrent: c.settings(),
+	}
+}
+
+func (c *InstallCommand) installAll(ctx context.Context, instances []config.Instance) ([]installResult, error) {
+	var results []installResult
+	err := forEachInstance(instances, func(instance config.Instance) error {
+		result, err := c.install(ctx, instance)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+		return nil
+	})
+	return results, err
+}
+
+func (c *InstallCommand) install(ctx context.Context, instance config.Instance) (installResult, error) {
+	vtManager, closeProvider, err := c.newVTManager(ctx, instance)
+	if err != nil {
+		return installResult{}, err
+	}
+	defer closeProvider()
+
+	// An archive is installed as is, so only an exact version from flags applies to it
+	var result vuetorrent.InstallResult
+	switch {
+	case c.FromFile != "":
+		result, err = vtManager.InstallArchive(ctx, c.FromFile, c.Version, instance.Directory)
+	case c.FromUrl != "":
+		result, err = vtManager.InstallFromUrl(ctx, c.FromUrl, c.Version, instance.Directory)
+	default:
+		result, err = vtManager.Install(ctx, instance.Version, instance.Directory)
+	}
+	if err != nil {
+		return installResult{}, err
+	}
+
+	if instance.Qbittorrent.Url != "" {
+		if _, err := configureWebUI(instance.Qbittorrent, instance.Directory, c.global.httpTransport()); err != nil {
+			return installResult{}, err
+		}
+	}
+
+	return installResult{
+		Instance:        instance.Name,
+		PreviousVersion: result.PreviousVersion,
+		Version:         result.Version,
+		Path:            result.Directory,
+		Changed:         result.Changed,
+	}, nil
+}
+
+func (c *InstallCommand) newVTManager(ctx context.Context, instance config.Instance) (vuetorrent.VTManager, func(), error) {
+	assetPattern, err := c.assetPattern()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signatureVerifier, err := c.signatureVerifier()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider, closeProvider, err := c.newProvider(ctx, c.global)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vuetorrent.NewVTManager(provider, vuetorrent.Config{
+		BackupDir:         instance.BackupDir,
+		KeepBackups:       c.KeepBackups,
+		Channel:           vuetorrent.Channel(instance.Channel),
+		AssetPattern:      assetPattern,
+		MaxExtractSize:    c.MaxExtractSize * 1024 * 1024,
+		MaxExtractFiles:   c.MaxExtractFiles,
+		HttpClient:        c.global.httpClient(),
+		OnProgress:        newProgressReporter(),
+		LimitRate:         c.limitRate(),
+		ArchiveCache:      c.global.archiveCache(),
+		DownloadDir:       c.global.partialDownloadsDir(),
+		SignatureVerifier: signatureVerifier,
+		RequireSignature:  c.RequireSignature,
+	}), closeProvider, nil
 }
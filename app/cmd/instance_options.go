@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"n1kit0s/vt-manager/app/config"
+)
+
+var errMissingDirectory = errors.New("the required flag `-d, --dir' was not specified and no instances are configured")
+
+type InstanceOptions struct {
+	Instance string `short:"i" long:"instance" description:"Instance from the config file (default: all instances)" env:"VT_MANAGER_INSTANCE"`
+}
+
+// instances returns the VueTorrent instances a command works on. Non-empty values of overrides
+// (flags and env vars) replace values from the config file. If --dir is set without --instance,
+// the config file is not used.
+func (o InstanceOptions) instances(configFile string, overrides config.Instance) ([]config.Instance, error) {
+	if overrides.Directory != "" && o.Instance == "" {
+		return []config.Instance{overrides}, nil
+	}
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Instances) == 0 {
+		if o.Instance != "" {
+			return nil, fmt.Errorf("instance [%s] not found. no instances are configured", o.Instance)
+		}
+		return nil, errMissingDirectory
+	}
+	slog.Debug("Loaded config", "path", cfg.Path, "instances", len(cfg.Instances))
+
+	selected, err := cfg.Select(o.Instance)
+	if err != nil {
+		return nil, err
+	}
+	for i := range selected {
+		selected[i] = selected[i].Override(overrides)
+	}
+
+	return selected, nil
+}
+
+// forEachInstance runs fn for every instance. A failed instance doesn't stop the others.
+func forEachInstance(instances []config.Instance, fn func(instance config.Instance) error) error {
+	var errs []error
+	for _, instance := range instances {
+		err := fn(instance)
+		if err == nil {
+			continue
+		}
+
+		if instance.Name != "" {
+			err = fmt.Errorf("instance [%s]. %s", instance.Name, err.Error())
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// printInstanceResults prints a single result for a directory passed with flags
+// and a list of results for instances from the config file. Nothing is printed if all instances failed.
+func printInstanceResults[T any](global *GlobalOptions, instances []config.Instance, results []T, printText func()) error {
+	if len(results) == 0 {
+		return nil
+	}
+	if len(instances) == 1 && instances[0].Name == "" && len(results) == 1 {
+		return global.printResult(results[0], printText)
+	}
+	return global.printResult(results, printText)
+}
@@ -2,26 +2,55 @@ package cmd
 
 import (
 	"fmt"
-	"n1kit0s/vt-manager/app/github"
 	"n1kit0s/vt-manager/app/vuetorrent"
 )
 
 type ListCommand struct {
-	GithubApiKey string `short:"k" long:"api-key" required:"true" description:"Github API key" env:"GITHUB_API_KEY"`
+	Channel string `short:"c" long:"channel" default:"stable" choice:"stable" choice:"prerelease" description:"Release channel" env:"VUETORRENT_CHANNEL"`
+
+	SourceOptions `group:"Release source options"`
+
+	globalCommand
 }
 
 func (c *ListCommand) Execute(args []string) error {
-	var githubClient = github.NewClient(c.GithubApiKey)
-	var vtManager = vuetorrent.NewVTManager(githubClient)
+	ctx, stop := signalContext()
+	defer stop()
+
+	assetPattern, err := c.assetPattern()
+	if err != nil {
+		return err
+	}
+
+	provider, closeProvider, err := c.newProvider(ctx, c.global)
+	if err != nil {
+		return err
+	}
+	defer closeProvider()
+
+	var vtManager = vuetorrent.NewVTManager(provider, vuetorrent.Config{
+		Channel:      vuetorrent.Channel(c.Channel),
+		AssetPattern: assetPattern,
+	})
 
-	releases, err := vtManager.GetAllReleases()
+	releases, err := vtManager.GetAllReleases(ctx)
 	if err != nil {
 		return err
 	}
 
+	var result = make([]releaseResult, 0, len(releases))
 	for _, release := range releases {
-		fmt.Println(release.Version)
+		result = append(result, releaseResult{
+			Version:     release.Version,
+			PublishedAt: release.PublishedAt,
+			Prerelease:  release.Prerelease,
+			AssetUrl:    release.DownloadUrl,
+		})
 	}
 
-	return nil
+	return c.global.printResult(result, func() {
+		for _, release := range releases {
+			fmt.Println(release.Version)
+		}
+	})
 }
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type OutputFormat string
+
+const (
+	OutputText OutputFormat = "text"
+	OutputJson OutputFormat = "json"
+	OutputYaml OutputFormat = "yaml"
+)
+
+// printResult writes the command result to stdout in the format selected with --output.
+// printText is used for the text format and may be nil if the logs are enough.
+func (o *GlobalOptions) printResult(result any, printText func()) error {
+	switch OutputFormat(o.Output) {
+	case OutputJson:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case OutputYaml:
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	if printText != nil {
+		printText()
+	}
+	return nil
+}
+
+type infoResult struct {
+	Instance    string    `json:"instance,omitempty" yaml:"instance,omitempty"`
+	Version     string    `json:"version" yaml:"version"`
+	Path        string    `json:"path" yaml:"path"`
+	InstalledAt time.Time `json:"installed_at" yaml:"installed_at"`
+}
+
+type releaseResult struct {
+	Version     string    `json:"version" yaml:"version"`
+	PublishedAt time.Time `json:"published_at" yaml:"published_at"`
+	Prerelease  bool      `json:"prerelease" yaml:"prerelease"`
+	AssetUrl    string    `json:"asset_url" yaml:"asset_url"`
+}
+
+type installResult struct {
+	Instance        string `json:"instance,omitempty" yaml:"instance,omitempty"`
+	PreviousVersion string `json:"previous_version" yaml:"previous_version"`
+	Version         string `json:"version" yaml:"version"`
+	Path            string `json:"path" yaml:"path"`
+	Changed         bool   `json:"changed" yaml:"changed"`
+}
+
+type checkResult struct {
+	Instance         string `json:"instance,omitempty" yaml:"instance,omitempty"`
+	InstalledVersion string `json:"installed_version" yaml:"installed_version"`
+	AvailableVersion string `json:"available_version" yaml:"available_version"`
+	Status           string `json:"status" yaml:"status"`
+}
+
+type revisionResult struct {
+	Revision string `json:"revision" yaml:"revision"`
+}
+
+type backupResult struct {
+	Version   string    `json:"version" yaml:"version"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	Size      int64     `json:"size" yaml:"size"`
+	Path      string    `json:"path" yaml:"path"`
+}
+
+type cachedArchiveResult struct {
+	Version string    `json:"version" yaml:"version"`
+	Digest  string    `json:"digest" yaml:"digest"`
+	Size    int64     `json:"size" yaml:"size"`
+	UsedAt  time.Time `json:"used_at" yaml:"used_at"`
+	Path    string    `json:"path" yaml:"path"`
+}
+
+type rollbackResult struct {
+	PreviousVersion string `json:"previous_version" yaml:"previous_version"`
+	Version         string `json:"version" yaml:"version"`
+	Path            string `json:"path" yaml:"path"`
+}
+
+type cacheClearResult struct {
+	Dir string `json:"dir" yaml:"dir"`
+}
+
+type qbittorrentResult struct {
+	Url      string `json:"url" yaml:"url"`
+	WebUIDir string `json:"webui_dir" yaml:"webui_dir"`
+}
+
+type bundleResult struct {
+	Path     string   `json:"path" yaml:"path"`
+	Versions []string `json:"versions" yaml:"versions"`
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"n1kit0s/vt-manager/app/vuetorrent"
+	"os"
+	"strings"
+	"time"
+)
+
+type DownloadOptions struct {
+	LimitRate int64 `long:"limit-rate" default:"0" description:"Maximum download speed in KiB/s (0 - no limit)" env:"VT_MANAGER_LIMIT_RATE"`
+}
+
+func (o DownloadOptions) limitRate() int64 {
+	return o.LimitRate * 1024
+}
+
+// progressLogInterval is the time between progress log lines when stderr is not a terminal.
+const progressLogInterval = 5 * time.Second
+
+const progressBarWidth = 30
+
+// newProgressReporter draws a progress bar if stderr is a terminal and logs progress periodically otherwise.
+func newProgressReporter() vuetorrent.ProgressFunc {
+	if isTerminal(os.Stderr) {
+		return progressBar(os.Stderr)
+	}
+	return progressLogger(progressLogInterval)
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func progressBar(writer io.Writer) vuetorrent.ProgressFunc {
+	return func(progress vuetorrent.Progress) {
+		var bar = strings.Repeat(" ", progressBarWidth)
+		var percent = "    "
+		if progress.Total > 0 {
+			filled := int(progress.Downloaded * progressBarWidth / progress.Total)
+			filled = min(filled, progressBarWidth)
+			bar = strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+			percent = fmt.Sprintf("%3d%%", progress.Downloaded*100/progress.Total)
+		}
+
+		line := fmt.Sprintf("[%s] %s %s/%s %s/s", bar, percent, formatSize(progress.Downloaded), formatTotal(progress.Total), formatSize(int64(progress.Rate)))
+		if progress.ETA > 0 {
+			line += " ETA " + progress.ETA.Round(time.Second).String()
+		}
+
+		// Pad the line to erase leftovers of a longer previous one
+		fmt.Fprintf(writer, "\r%-80s", line)
+		if progress.Done {
+			fmt.Fprintln(writer)
+		}
+	}
+}
+
+func progressLogger(interval time.Duration) vuetorrent.ProgressFunc {
+	var loggedAt time.Time
+	return func(progress vuetorrent.Progress) {
+		if !progress.Done && time.Since(loggedAt) < interval {
+			return
+		}
+		loggedAt = time.Now()
+
+		slog.Info("Download progress",
+			"url", progress.Url,
+			"downloaded", formatSize(progress.Downloaded),
+			"total", formatTotal(progress.Total),
+			"rate", formatSize(int64(progress.Rate))+"/s",
+			"eta", progress.ETA.Round(time.Second),
+		)
+	}
+}
+
+func formatTotal(total int64) string {
+	if total < 0 {
+		return "?"
+	}
+	return formatSize(total)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import "errors"
+
+var errMissingQbtUrl = errors.New("the required flag `--qbt-url' was not specified")
+
+type QbittorrentCommand struct {
+	ConfigureCmd QbittorrentConfigureCommand `command:"configure" description:"Enable VueTorrent as qBittorrent alternative WebUI"`
+}
+
+type QbittorrentConfigureCommand struct {
+	Directory string `short:"d" long:"dir" required:"true" description:"VueTorrent directory" env:"VUETORRENT_DIRECTORY"`
+
+	QbittorrentOptions `group:"qBittorrent options"`
+
+	globalCommand
+}
+
+func (c *QbittorrentConfigureCommand) Execute(args []string) error {
+	// The url is optional for install, but this command can't do anything without it
+	if c.QbtUrl == "" {
+		return errMissingQbtUrl
+	}
+
+	webUIDir, err := configureWebUI(c.settings(), c.Directory, c.global.httpTransport())
+	if err != nil {
+		return err
+	}
+
+	return c.global.printResult(qbittorrentResult{Url: c.QbtUrl, WebUIDir: webUIDir}, nil)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"n1kit0s/vt-manager/app/config"
+	"n1kit0s/vt-manager/app/qbittorrent"
+	"net/http"
+	"path/filepath"
+)
+
+type QbittorrentOptions struct {
+	QbtUrl      string `long:"qbt-url" description:"qBittorrent WebUI url. If set, VueTorrent is enabled as alternative WebUI" env:"QBITTORRENT_URL"`
+	QbtUser     string `long:"qbt-user" description:"qBittorrent WebUI username (default: admin)" env:"QBITTORRENT_USER"`
+	QbtPass     string `long:"qbt-pass" description:"qBittorrent WebUI password" env:"QBITTORRENT_PASSWORD"`
+	QbtWebUIDir string `long:"qbt-webui-dir" description:"VueTorrent directory as seen by qBittorrent (default: absolute path of --dir)" env:"QBITTORRENT_WEBUI_DIRECTORY"`
+}
+
+func (o QbittorrentOptions) settings() config.Qbittorrent {
+	return config.Qbittorrent{
+		Url:      o.QbtUrl,
+		User:     o.QbtUser,
+		Password: o.QbtPass,
+		WebUIDir: o.QbtWebUIDir,
+	}
+}
+
+// configureWebUI enables VueTorrent from directory as qBittorrent alternative WebUI. It returns
+// the directory qBittorrent was configured with.
+func configureWebUI(settings config.Qbittorrent, directory string, transport http.RoundTripper) (string, error) {
+	client, err := qbittorrent.NewClient(qbittorrent.Config{
+		Url:       settings.Url,
+		Username:  settings.User,
+		Password:  settings.Password,
+		Transport: transport,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	webUIDir := settings.WebUIDir
+	if webUIDir == "" {
+		webUIDir, err = filepath.Abs(directory)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return webUIDir, client.EnableAlternativeWebUI(webUIDir)
+}
@@ -5,11 +5,14 @@ import (
 	"log/slog"
 )
 
-type RevisionCommand struct{}
+type RevisionCommand struct {
+	globalCommand
+}
 
 var version string
 
 func (c *RevisionCommand) Execute(args []string) error {
-	slog.Info(fmt.Sprintf("Revision: %s", version))
-	return nil
+	return c.global.printResult(revisionResult{Revision: version}, func() {
+		slog.Info(fmt.Sprintf("Revision: %s", version))
+	})
 }
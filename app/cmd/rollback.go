@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"n1kit0s/vt-manager/app/vuetorrent"
+	"path/filepath"
+)
+
+type RollbackCommand struct {
+	Version   string `long:"to" optional:"true" description:"Version to roll back to (default: the most recent backup)"`
+	Directory string `short:"d" long:"dir" required:"true" description:"VueTorrent directory" env:"VUETORRENT_DIRECTORY"`
+
+	BackupOptions `group:"Backup options"`
+
+	globalCommand
+}
+
+func (c *RollbackCommand) Execute(args []string) error {
+	var backups = c.newBackupStore(c.Directory)
+
+	// An empty previous version means nothing was installed
+	previousVersion, _ := vuetorrent.GetInstalledVersion(c.Directory)
+	if previousVersion == "unknown" {
+		previousVersion = ""
+	}
+
+	backup, err := backups.Restore(c.Version, c.Directory)
+	if err != nil {
+		return err
+	}
+
+	var result = rollbackResult{PreviousVersion: previousVersion, Version: backup.Version, Path: filepath.Clean(c.Directory)}
+	return c.global.printResult(result, func() {
+		slog.Info(fmt.Sprintf("Rolled back to version: %s", backup.Version))
+	})
+}
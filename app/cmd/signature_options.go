@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"errors"
+	"n1kit0s/vt-manager/app/vuetorrent"
+)
+
+type SignatureOptions struct {
+	SignatureKey     string `long:"signature-key" description:"minisign or OpenPGP public key file. Release archives are verified against their .minisig, .asc or .sig asset" env:"VUETORRENT_SIGNATURE_KEY"`
+	RequireSignature bool   `long:"require-signature" description:"Refuse to install archives without a valid signature" env:"VUETORRENT_REQUIRE_SIGNATURE"`
+}
+
+// signatureVerifier returns nil if no key is configured. It fails before anything is downloaded
+// if a signature is required without a key.
+func (o SignatureOptions) signatureVerifier() (vuetorrent.SignatureVerifier, error) {
+	if o.SignatureKey == "" {
+		if o.RequireSignature {
+			return nil, errors.New("--require-signature needs a public key. Set --signature-key")
+		}
+		return nil, nil
+	}
+	return vuetorrent.LoadSignatureVerifier(o.SignatureKey)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"n1kit0s/vt-manager/app/bundle"
+	"n1kit0s/vt-manager/app/gitea"
+	"n1kit0s/vt-manager/app/github"
+	"n1kit0s/vt-manager/app/gitlab"
+	"n1kit0s/vt-manager/app/vuetorrent"
+	"net/http"
+	"time"
+)
+
+type SourceOptions struct {
+	Source           string        `long:"source" default:"github" choice:"github" choice:"gitea" choice:"forgejo" choice:"gitlab" description:"Service hosting VueTorrent releases" env:"VUETORRENT_SOURCE"`
+	SourceUrl        string        `long:"source-url" description:"Gitea/Forgejo or GitLab instance url, e.g. https://codeberg.org (default for GitLab: https://gitlab.com)" env:"VUETORRENT_SOURCE_URL"`
+	ApiToken         string        `short:"k" long:"api-token" description:"Access token for the release source API (optional, anonymous requests have a lower rate limit)" env:"VT_API_TOKEN"`
+	GithubApiKey     string        `long:"api-key" description:"Deprecated alias of --api-token" env:"GITHUB_API_KEY"`
+	GithubUrl        string        `long:"github-url" default:"https://api.github.com" description:"GitHub API url (for GitHub Enterprise use https://<host>/api/v3)" env:"GITHUB_URL"`
+	Repository       string        `long:"repo" default:"VueTorrent/VueTorrent" description:"Repository with VueTorrent releases in owner/name form (GitLab project path may contain subgroups)" env:"VUETORRENT_REPOSITORY"`
+	AssetPattern     string        `long:"asset-pattern" default:"vuetorrent.zip" description:"Release asset to install. Glob or regexp enclosed in slashes (/.../)" env:"VUETORRENT_ASSET_PATTERN"`
+	PerPage          int           `long:"per-page" default:"100" description:"Number of releases requested per page" env:"GITHUB_PER_PAGE"`
+	MaxPages         int           `long:"max-pages" default:"0" description:"Maximum number of release pages to fetch (0 - no limit)" env:"GITHUB_MAX_PAGES"`
+	MaxRateLimitWait time.Duration `long:"max-rate-limit-wait" default:"1m" description:"Maximum time to wait for GitHub rate limit reset" env:"GITHUB_MAX_RATE_LIMIT_WAIT"`
+	Bundle           string        `long:"bundle" description:"Read releases from a bundle created by 'bundle export' instead of --source" env:"VUETORRENT_BUNDLE"`
+}
+
+// newProvider returns the release provider and a function closing it, e.g. removing an extracted bundle.
+func (o SourceOptions) newProvider(ctx context.Context, global *GlobalOptions) (vuetorrent.ReleaseProvider, func(), error) {
+	if o.Bundle != "" {
+		bundleClient, err := bundle.Open(ctx, o.Bundle)
+		if err != nil {
+			return nil, nil, err
+		}
+		return vuetorrent.GithubProvider{Client: bundleClient}, func() {
+			if err := bundleClient.Close(); err != nil {
+				slog.Warn("Can't remove extracted bundle", "dir", bundleClient.Dir, "error", err.Error())
+			}
+		}, nil
+	}
+
+	provider, err := o.newSourceProvider(global.apiTransport())
+	return provider, func() {}, err
+}
+
+func (o SourceOptions) newSourceProvider(transport http.RoundTripper) (vuetorrent.ReleaseProvider, error) {
+
+	switch o.Source {
+	case "gitea", "forgejo":
+		if o.SourceUrl == "" {
+			return nil, fmt.Errorf("--source-url is required for %s", o.Source)
+		}
+		if err := github.ValidateRepository(o.Repository); err != nil {
+			return nil, err
+		}
+		return vuetorrent.GiteaProvider{Client: gitea.NewClient(gitea.Config{
+			BaseUrl:    o.SourceUrl,
+			Repository: o.Repository,
+			ApiKey:     o.apiToken(),
+			PerPage:    o.PerPage,
+			MaxPages:   o.MaxPages,
+			Transport:  transport,
+		})}, nil
+	case "gitlab":
+		return vuetorrent.GitlabProvider{Client: gitlab.NewClient(gitlab.Config{
+			BaseUrl:    o.SourceUrl,
+			Repository: o.Repository,
+			ApiKey:     o.apiToken(),
+			PerPage:    o.PerPage,
+			MaxPages:   o.MaxPages,
+			Transport:  transport,
+		})}, nil
+	}
+
+	if err := github.ValidateRepository(o.Repository); err != nil {
+		return nil, err
+	}
+
+	return vuetorrent.GithubProvider{Client: github.NewClient(github.Config{
+		ApiKey:           o.apiToken(),
+		BaseUrl:          o.GithubUrl,
+		Repository:       o.Repository,
+		PerPage:          o.PerPage,
+		MaxPages:         o.MaxPages,
+		MaxRateLimitWait: o.MaxRateLimitWait,
+		Transport:        transport,
+	})}, nil
+}
+
+// apiToken prefers --api-token over the deprecated --api-key.
+func (o SourceOptions) apiToken() string {
+	if o.ApiToken != "" {
+		return o.ApiToken
+	}
+	if o.GithubApiKey != "" {
+		slog.Warn("--api-key and GITHUB_API_KEY are deprecated. Use --api-token or VT_API_TOKEN")
+	}
+	return o.GithubApiKey
+}
+
+func (o SourceOptions) assetPattern() (vuetorrent.AssetPattern, error) {
+	return vuetorrent.ParseAssetPattern(o.AssetPattern)
+}
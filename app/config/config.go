@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"n1kit0s/vt-manager/app/vuetorrent"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultFileName = "vt-manager.yaml"
+
+// Qbittorrent is the qBittorrent WebUI where an instance is enabled as alternative WebUI.
+type Qbittorrent struct {
+	Url      string `yaml:"url"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	WebUIDir string `yaml:"webui_dir"`
+}
+
+// Instance is a VueTorrent directory managed by vt-manager.
+type Instance struct {
+	// Name is the key of the instance in the config file. Empty for an instance built from flags.
+	Name        string      `yaml:"-"`
+	Directory   string      `yaml:"dir"`
+	Version     string      `yaml:"version"`
+	Channel     string      `yaml:"channel"`
+	BackupDir   string      `yaml:"backup_dir"`
+	Qbittorrent Qbittorrent `yaml:"qbittorrent"`
+}
+
+type Config struct {
+	// Path of the loaded file. Empty if no config file was found.
+	Path      string              `yaml:"-"`
+	Instances map[string]Instance `yaml:"instances"`
+}
+
+// DefaultPaths are searched in order when --config isn't set.
+func DefaultPaths() []string {
+	var paths = []string{DefaultFileName}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(configDir, "vt-manager", "config.yaml"))
+	}
+	return append(paths, "/etc/vt-manager/config.yaml")
+}
+
+// Load reads the config file. If path is empty, the first existing file from DefaultPaths is used
+// and an empty Config is returned when there is none.
+func Load(path string) (Config, error) {
+	if path == "" {
+		for _, defaultPath := range DefaultPaths() {
+			if _, err := os.Stat(defaultPath); err == nil {
+				path = defaultPath
+				break
+			}
+		}
+		if path == "" {
+			return Config{}, nil
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config %s. %s", path, err.Error())
+	}
+
+	config, err := Parse(content)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config %s. %s", path, err.Error())
+	}
+	config.Path = path
+
+	return config, nil
+}
+
+func Parse(content []byte) (Config, error) {
+	var config Config
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, err
+	}
+
+	for name, instance := range config.Instances {
+		if instance.Directory == "" {
+			return Config{}, fmt.Errorf("instance [%s] has no dir", name)
+		}
+		if _, err := vuetorrent.ParseChannel(instance.Channel); err != nil {
+			return Config{}, fmt.Errorf("instance [%s] has invalid channel. %s", name, err.Error())
+		}
+		instance.Name = name
+		config.Instances[name] = instance
+	}
+
+	return config, nil
+}
+
+// Select returns the instance with the given name or all instances sorted by name if name is empty.
+func (c Config) Select(name string) ([]Instance, error) {
+	if name != "" {
+		instance, found := c.Instances[name]
+		if !found {
+			return nil, fmt.Errorf("instance [%s] not found in config", name)
+		}
+		return []Instance{instance}, nil
+	}
+
+	var instances = make([]Instance, 0, len(c.Instances))
+	for _, instance := range c.Instances {
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
+
+	return instances, nil
+}
+
+// Override returns a copy of the instance with every non-empty value of overrides applied.
+func (i Instance) Override(overrides Instance) Instance {
+	i.Directory = overrideValue(i.Directory, overrides.Directory)
+	i.Version = overrideValue(i.Version, overrides.Version)
+	i.Channel = overrideValue(i.Channel, overrides.Channel)
+	i.BackupDir = overrideValue(i.BackupDir, overrides.BackupDir)
+	i.Qbittorrent.Url = overrideValue(i.Qbittorrent.Url, overrides.Qbittorrent.Url)
+	i.Qbittorrent.User = overrideValue(i.Qbittorrent.User, overrides.Qbittorrent.User)
+	i.Qbittorrent.Password = overrideValue(i.Qbittorrent.Password, overrides.Qbittorrent.Password)
+	i.Qbittorrent.WebUIDir = overrideValue(i.Qbittorrent.WebUIDir, overrides.Qbittorrent.WebUIDir)
+	return i
+}
+
+func overrideValue(value string, override string) string {
+	if override != "" {
+		return override
+	}
+	return value
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	// Run
+	config, err := Load("testdata/config.yaml")
+	if err != nil {
+		t.Fatalf("Can't load config. Error: %s", err.Error())
+	}
+
+	expected := map[string]Instance{
+		"main": {
+			Name:      "main",
+			Directory: "/srv/qbittorrent/vuetorrent",
+			Version:   "^2",
+			Qbittorrent: Qbittorrent{
+				Url:      "http://localhost:8080",
+				User:     "admin",
+				Password: "adminadmin",
+				WebUIDir: "/vuetorrent",
+			},
+		},
+		"testing": {
+			Name:      "testing",
+			Directory: "/srv/qbittorrent-testing/vuetorrent",
+			Channel:   "prerelease",
+			BackupDir: "/srv/backups",
+		},
+	}
+
+	if config.Path != "testdata/config.yaml" {
+		t.Errorf("Unexpected config path %s", config.Path)
+	}
+	if !reflect.DeepEqual(expected, config.Instances) {
+		t.Fatalf("Instances don't match. Expected: %+v | Actual: %+v", expected, config.Instances)
+	}
+}
+
+func TestLoadWithoutConfigFile(t *testing.T) {
+	// Setup
+	workDir, _ := os.Getwd()
+	defer os.Chdir(workDir)
+	os.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	// Run
+	config, err := Load("")
+	if err != nil {
+		t.Fatalf("Missing default config must not fail. Error: %s", err.Error())
+	}
+
+	if config.Path != "" || len(config.Instances) != 0 {
+		t.Fatalf("Expected empty config. Actual: %+v", config)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("Expected error for missing config passed explicitly")
+	}
+}
+
+func TestLoadFromDefaultPath(t *testing.T) {
+	// Setup
+	workDir, _ := os.Getwd()
+	defer os.Chdir(workDir)
+	os.Chdir(t.TempDir())
+	os.WriteFile(DefaultFileName, []byte("instances:\n  main:\n    dir: ./vuetorrent\n"), 0644)
+
+	// Run
+	config, err := Load("")
+	if err != nil {
+		t.Fatalf("Can't load config. Error: %s", err.Error())
+	}
+
+	if config.Path != DefaultFileName || config.Instances["main"].Directory != "./vuetorrent" {
+		t.Fatalf("Unexpected config %+v", config)
+	}
+}
+
+func TestParseInvalidConfig(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":   "instances:\n  main:\n    dir: ./vt\n    directory: ./vt\n",
+		"missing dir":     "instances:\n  main:\n    version: 2.3.0\n",
+		"invalid channel": "instances:\n  main:\n    dir: ./vt\n    channel: nightly\n",
+		"not yaml":        "instances: [",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(content)); err == nil {
+				t.Fatal("Expected error")
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	// Setup
+	config, err := Load("testdata/config.yaml")
+	if err != nil {
+		t.Fatalf("Can't load config. Error: %s", err.Error())
+	}
+
+	// Run
+	all, err := config.Select("")
+	if err != nil || len(all) != 2 || all[0].Name != "main" || all[1].Name != "testing" {
+		t.Fatalf("Expected all instances sorted by name. Actual: %+v, %v", all, err)
+	}
+
+	selected, err := config.Select("testing")
+	if err != nil || len(selected) != 1 || selected[0].Name != "testing" {
+		t.Fatalf("Expected testing instance. Actual: %+v, %v", selected, err)
+	}
+
+	if _, err := config.Select("unknown"); err == nil {
+		t.Fatal("Expected error for unknown instance")
+	}
+}
+
+func TestOverride(t *testing.T) {
+	// Setup
+	instance := Instance{
+		Name:        "main",
+		Directory:   "/srv/vuetorrent",
+		Version:     "^2",
+		Channel:     "stable",
+		Qbittorrent: Qbittorrent{Url: "http://localhost:8080", User: "admin"},
+	}
+
+	// Run
+	actual := instance.Override(Instance{Version: "2.3.0", Qbittorrent: Qbittorrent{Password: "secret"}})
+
+	expected := Instance{
+		Name:        "main",
+		Directory:   "/srv/vuetorrent",
+		Version:     "2.3.0",
+		Channel:     "stable",
+		Qbittorrent: Qbittorrent{Url: "http://localhost:8080", User: "admin", Password: "secret"},
+	}
+	if actual != expected {
+		t.Fatalf("Instances don't match. Expected: %+v | Actual: %+v", expected, actual)
+	}
+}
@@ -0,0 +1,150 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadUrl string `json:"browser_download_url"`
+	Size        int64  `json:"size"`
+}
+
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Client reads releases from the Gitea API. Forgejo (e.g. Codeberg) serves the same API.
+type Client interface {
+	GetReleases(ctx context.Context) ([]Release, error)
+	GetReleaseByTag(ctx context.Context, tag string) (Release, error)
+}
+
+// DefaultPerPage is the page size used when PerPage is not set. Gitea caps page size at 50 by default.
+const DefaultPerPage = 50
+
+type Config struct {
+	// BaseUrl is the instance url, e.g. https://codeberg.org. The API path is added by the client.
+	BaseUrl string
+	// Repository is the owner/name of the repository with VueTorrent releases.
+	Repository string
+	// ApiKey is an optional access token.
+	ApiKey string
+	// PerPage is the page size requested from the releases endpoint. Zero means DefaultPerPage.
+	PerPage int
+	// MaxPages limits how many pages GetReleases requests. Zero means no limit.
+	MaxPages int
+	// Transport executes the requests, e.g. a caching one. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+type DefaultClient struct {
+	ApiKey     string
+	Client     *http.Client
+	BaseUrl    string
+	Repository string
+	PerPage    int
+	MaxPages   int
+}
+
+func NewClient(config Config) Client {
+	return &DefaultClient{
+		ApiKey:     config.ApiKey,
+		Client:     &http.Client{Transport: config.Transport},
+		BaseUrl:    strings.TrimSuffix(config.BaseUrl, "/"),
+		Repository: config.Repository,
+		PerPage:    config.PerPage,
+		MaxPages:   config.MaxPages,
+	}
+}
+
+// GetReleases requests pages until X-Total-Count releases are received, an empty page is returned
+// or MaxPages is reached. The server may return less than PerPage releases if its limit is lower.
+func (gitea *DefaultClient) GetReleases(ctx context.Context) ([]Release, error) {
+	var perPage = gitea.PerPage
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+
+	var releases = []Release{}
+	for page := 1; gitea.MaxPages <= 0 || page <= gitea.MaxPages; page++ {
+		var releasesUrl = fmt.Sprintf("%s/releases?page=%d&limit=%d", gitea.repositoryUrl(), page, perPage)
+
+		var pageReleases []Release
+		resp, err := gitea.get(ctx, releasesUrl, &pageReleases)
+		if err != nil {
+			return []Release{}, fmt.Errorf("failed to get releases. %w", err)
+		}
+
+		releases = append(releases, pageReleases...)
+
+		total, err := strconv.Atoi(resp.Header.Get("X-Total-Count"))
+		if len(pageReleases) == 0 || (err == nil && len(releases) >= total) {
+			break
+		}
+	}
+
+	return releases, nil
+}
+
+func (gitea *DefaultClient) GetReleaseByTag(ctx context.Context, tag string) (Release, error) {
+	var releaseUrl = fmt.Sprintf("%s/releases/tags/%s", gitea.repositoryUrl(), url.PathEscape(tag))
+
+	var release Release
+	if _, err := gitea.get(ctx, releaseUrl, &release); err != nil {
+		return Release{}, fmt.Errorf("failed to get release by tag. %w", err)
+	}
+
+	return release, nil
+}
+
+func (gitea *DefaultClient) repositoryUrl() string {
+	return fmt.Sprintf("%s/api/v1/repos/%s", gitea.BaseUrl, gitea.Repository)
+}
+
+func (gitea *DefaultClient) get(ctx context.Context, requestUrl string, result any) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if gitea.ApiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", gitea.ApiKey))
+	}
+
+	resp, err := gitea.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response. %s", err.Error())
+	}
+
+	if err := httpclient.CheckStatus(resp, body); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: [%s]. %s", string(body), err.Error())
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,220 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetReleases(t *testing.T) {
+	// Setup
+	server := mockServerWithResponse(t, "/api/v1/repos/mirror/VueTorrent/releases", "testdata/releases.json")
+	defer server.Close()
+
+	giteaClient := createGiteaClient(server)
+
+	// Run
+	releases, err := giteaClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expectedReleases := []Release{
+		{
+			TagName:     "v2.3.0",
+			Name:        "v2.3.0",
+			PublishedAt: mustParseTime(t, "2023-11-29T07:28:55Z"),
+			Assets: []Asset{
+				{
+					Name:        "vuetorrent.zip",
+					DownloadUrl: "https://forgejo.example.com/mirror/VueTorrent/releases/download/v2.3.0/vuetorrent.zip",
+					Size:        3190859,
+				},
+				{
+					Name:        "SHA256SUMS",
+					DownloadUrl: "https://forgejo.example.com/mirror/VueTorrent/releases/download/v2.3.0/SHA256SUMS",
+					Size:        81,
+				},
+			},
+		},
+		{
+			TagName:     "v2.3.0-beta.1",
+			Name:        "v2.3.0-beta.1",
+			Prerelease:  true,
+			PublishedAt: mustParseTime(t, "2023-11-25T10:00:00Z"),
+			Assets: []Asset{
+				{
+					Name:        "vuetorrent.zip",
+					DownloadUrl: "https://forgejo.example.com/mirror/VueTorrent/releases/download/v2.3.0-beta.1/vuetorrent.zip",
+					Size:        3189012,
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(releases, expectedReleases) {
+		t.Errorf("\nGot: %+v \nExp: %+v", releases, expectedReleases)
+	}
+}
+
+func TestGetReleaseByTag(t *testing.T) {
+	// Setup
+	server := mockServerWithResponse(t, "/api/v1/repos/mirror/VueTorrent/releases/tags/v2.3.0", "testdata/release_by_tag.json")
+	defer server.Close()
+
+	giteaClient := createGiteaClient(server)
+
+	// Run
+	release, err := giteaClient.GetReleaseByTag(context.Background(), "v2.3.0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if release.TagName != "v2.3.0" || len(release.Assets) != 2 || release.Assets[0].Size != 3190859 {
+		t.Errorf("Unexpected release %+v", release)
+	}
+}
+
+func TestGetReleasesRequestsPages(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token foo" {
+			t.Errorf("Unexpected Authorization header [%s]", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("limit") != "5" {
+			t.Errorf("limit is not passed. Query: %s", r.URL.RawQuery)
+		}
+
+		// The server limit is lower than the requested page size
+		w.Header().Set("X-Total-Count", "3")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`[{"tag_name": "v2.3.0"}]`))
+		case "2":
+			w.Write([]byte(`[{"tag_name": "v2.2.0"}, {"tag_name": "v2.1.1"}]`))
+		default:
+			t.Errorf("Unexpected page requested: %s", r.URL.RawQuery)
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	giteaClient := &DefaultClient{ApiKey: "foo", Client: server.Client(), BaseUrl: server.URL, Repository: "mirror/VueTorrent", PerPage: 5}
+
+	// Run
+	releases, err := giteaClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(releases) != 3 || releases[2].TagName != "v2.1.1" {
+		t.Fatalf("Unexpected releases %+v", releases)
+	}
+}
+
+func TestGetReleasesStopsAtMaxPages(t *testing.T) {
+	// Setup
+	var requests = 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(fmt.Sprintf(`[{"tag_name": "v0.0.%d"}]`, requests)))
+	}))
+	defer server.Close()
+
+	giteaClient := &DefaultClient{Client: server.Client(), BaseUrl: server.URL, Repository: "mirror/VueTorrent", PerPage: 1, MaxPages: 3}
+
+	// Run
+	releases, err := giteaClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if requests != 3 || len(releases) != 3 {
+		t.Fatalf("Expected 3 requests and releases. Actual: %d requests, %d releases", requests, len(releases))
+	}
+}
+
+func TestGetReleasesStopsOnEmptyPage(t *testing.T) {
+	// Setup
+	var requests = 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"tag_name": "v2.3.0"}]`))
+	}))
+	defer server.Close()
+
+	giteaClient := &DefaultClient{Client: server.Client(), BaseUrl: server.URL, Repository: "mirror/VueTorrent"}
+
+	// Run
+	releases, err := giteaClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if requests != 2 || len(releases) != 1 {
+		t.Fatalf("Expected 2 requests and 1 release. Actual: %d requests, %d releases", requests, len(releases))
+	}
+}
+
+func TestGetReleaseByTagNotFound(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	giteaClient := createGiteaClient(server)
+
+	// Run
+	_, err := giteaClient.GetReleaseByTag(context.Background(), "v9.9.9")
+	if !errors.Is(err, httpclient.ErrNotFound) {
+		t.Fatalf("Expected not found error. Actual: %v", err)
+	}
+}
+
+func mockServerWithResponse(t *testing.T, expectedPath string, fileWithResponse string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != expectedPath {
+			t.Errorf("Unexpected path %s. Expected %s", r.URL.Path, expectedPath)
+		}
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			w.Write([]byte(`[]`))
+			return
+		}
+
+		content, err := os.ReadFile(fileWithResponse)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		w.Write(content)
+	}))
+}
+
+func createGiteaClient(server *httptest.Server) Client {
+	return &DefaultClient{
+		ApiKey:     "foo",
+		Client:     server.Client(),
+		BaseUrl:    server.URL,
+		Repository: "mirror/VueTorrent",
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return parsed
+}
@@ -1,95 +1,181 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"n1kit0s/vt-manager/app/httpclient"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Asset struct {
 	Name        string `json:"name"`
 	DownloadUrl string `json:"browser_download_url"`
+	Size        int64  `json:"size"`
+	// Digest is in <algorithm>:<hex> form, e.g. sha256:9f86d08... GitHub only fills it for newer uploads.
+	Digest string `json:"digest"`
 }
 
 type Release struct {
-	TagName string  `json:"tag_name"`
-	Assets  []Asset `json:"assets"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
 }
 
 type Client interface {
-	GetReleases() ([]Release, error)
-	GetReleaseByTag(tag string) (Release, error)
+	GetReleases(ctx context.Context) ([]Release, error)
+	GetReleaseByTag(ctx context.Context, tag string) (Release, error)
 }
 
-type DefaultClient struct {
-	ApiKey  string
-	Client  *http.Client
+const (
+	DefaultBaseUrl    = "https://api.github.com"
+	DefaultRepository = "VueTorrent/VueTorrent"
+)
+
+type Config struct {
+	// BaseUrl is the API root. For GitHub Enterprise it is usually https://<host>/api/v3.
 	BaseUrl string
+	// Repository is the owner/name of the repository with VueTorrent releases.
+	Repository string
+	// ApiKey is optional. Without it requests are anonymous and have a lower rate limit.
+	ApiKey string
+	// PerPage is the page size requested from the releases endpoint. Zero means GitHub's default.
+	PerPage int
+	// MaxPages limits how many pages GetReleases follows. Zero means no limit.
+	MaxPages int
+	// MaxRateLimitWait is the longest time to wait for a rate limit reset. Zero fails right away.
+	MaxRateLimitWait time.Duration
+	// Transport executes the requests, e.g. a caching one. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+type DefaultClient struct {
+	ApiKey           string
+	Client           *http.Client
+	BaseUrl          string
+	Repository       string
+	PerPage          int
+	MaxPages         int
+	MaxRateLimitWait time.Duration
+
+	sleep func(time.Duration)
 }
 
-func NewClient(apiKey string) Client {
+func NewClient(config Config) Client {
+	var baseUrl = strings.TrimSuffix(config.BaseUrl, "/")
+	if baseUrl == "" {
+		baseUrl = DefaultBaseUrl
+	}
+
 	return &DefaultClient{
-		ApiKey:  apiKey,
-		Client:  &http.Client{},
-		BaseUrl: "https://api.github.com",
+		ApiKey:           config.ApiKey,
+		Client:           &http.Client{Transport: config.Transport},
+		BaseUrl:          baseUrl,
+		Repository:       config.Repository,
+		PerPage:          config.PerPage,
+		MaxPages:         config.MaxPages,
+		MaxRateLimitWait: config.MaxRateLimitWait,
 	}
 }
 
-func (github *DefaultClient) GetReleases() ([]Release, error) {
-	var releasesUrl = fmt.Sprintf("%s/%s", github.BaseUrl, "repos/WDaan/VueTorrent/releases")
-	req, err := http.NewRequest("GET", releasesUrl, nil)
-	if err != nil {
-		return []Release{}, fmt.Errorf("failed to create releases request. %s", err.Error())
+// GetReleases returns all releases, following the rel="next" links of the Link header
+// until the last page or MaxPages is reached.
+func (github *DefaultClient) GetReleases(ctx context.Context) ([]Release, error) {
+	var releasesUrl = fmt.Sprintf("%s/repos/%s/releases", github.BaseUrl, github.repository())
+	if github.PerPage > 0 {
+		releasesUrl = fmt.Sprintf("%s?per_page=%s", releasesUrl, strconv.Itoa(github.PerPage))
 	}
 
-	req.Header.Add("Accept", "application/vnd.github+json")
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", github.ApiKey))
+	var githubReleases = []Release{}
+	for page := 1; releasesUrl != ""; page++ {
+		if github.MaxPages > 0 && page > github.MaxPages {
+			break
+		}
+
+		pageReleases, nextUrl, err := github.getReleasesPage(ctx, releasesUrl)
+		if err != nil {
+			return []Release{}, err
+		}
+
+		githubReleases = append(githubReleases, pageReleases...)
+		releasesUrl = nextUrl
+	}
 
-	resp, err := github.Client.Do(req)
+	return githubReleases, nil
+}
+
+func (github *DefaultClient) getReleasesPage(ctx context.Context, releasesUrl string) ([]Release, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", releasesUrl, nil)
 	if err != nil {
-		return []Release{}, fmt.Errorf("failed to retrieve releases from github. %s", err.Error())
+		return []Release{}, "", fmt.Errorf("failed to create releases request. %s", err.Error())
 	}
-	defer resp.Body.Close()
 
-	releasesBody, err := io.ReadAll(resp.Body)
+	resp, releasesBody, err := github.send(req)
 	if err != nil {
-		return []Release{}, fmt.Errorf("failed to read releases responce. %s", err.Error())
+		return []Release{}, "", fmt.Errorf("failed to retrieve releases from github. %w", err)
+	}
+
+	if err := httpclient.CheckStatus(resp, releasesBody); err != nil {
+		return []Release{}, "", fmt.Errorf("failed to get releases. %w", err)
 	}
 
 	var githubReleases []Release
 	err = json.Unmarshal(releasesBody, &githubReleases)
 	if err != nil {
-		return []Release{}, fmt.Errorf("failed to decode releases. response: [%s]. %s", string(releasesBody), err.Error())
+		return []Release{}, "", fmt.Errorf("failed to decode releases. response: [%s]. %s", string(releasesBody), err.Error())
 	}
 
-	return githubReleases, nil
+	nextUrl, err := nextPageUrl(resp.Header.Get("Link"), req.URL)
+	if err != nil {
+		return []Release{}, "", err
+	}
+
+	return githubReleases, nextUrl, nil
 }
 
-func (github *DefaultClient) GetReleaseByTag(tag string) (Release, error) {
-	var releasesUrl = fmt.Sprintf("%s/%s/%s", github.BaseUrl, "repos/WDaan/VueTorrent/releases/tags", tag)
-	req, err := http.NewRequest("GET", releasesUrl, nil)
-	if err != nil {
-		return Release{}, fmt.Errorf("failed to create 'get release by tag' request. %s", err.Error())
+var linkRegexp = regexp.MustCompile(`<([^>]*)>\s*;\s*rel="?([^";]*)"?`)
+
+// nextPageUrl extracts the rel="next" url from a Link header. It returns an empty string on the last page.
+func nextPageUrl(linkHeader string, requestUrl *url.URL) (string, error) {
+	for _, match := range linkRegexp.FindAllStringSubmatch(linkHeader, -1) {
+		if match[2] != "next" {
+			continue
+		}
+
+		nextUrl, err := requestUrl.Parse(match[1])
+		if err != nil {
+			return "", fmt.Errorf("failed to parse next page link [%s]. %s", match[1], err.Error())
+		}
+		return nextUrl.String(), nil
 	}
 
-	req.Header.Add("Accept", "application/vnd.github+json")
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", github.ApiKey))
+	return "", nil
+}
 
-	resp, err := github.Client.Do(req)
+func (github *DefaultClient) GetReleaseByTag(ctx context.Context, tag string) (Release, error) {
+	var releasesUrl = fmt.Sprintf("%s/repos/%s/releases/tags/%s", github.BaseUrl, github.repository(), url.PathEscape(tag))
+	req, err := http.NewRequestWithContext(ctx, "GET", releasesUrl, nil)
 	if err != nil {
-		return Release{}, fmt.Errorf("failed to retrieve release by tag from github. %s", err.Error())
+		return Release{}, fmt.Errorf("failed to create 'get release by tag' request. %s", err.Error())
 	}
-	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	resp, responseBody, err := github.send(req)
 	if err != nil {
-		return Release{}, fmt.Errorf("failed to read release by tag response. %s", err.Error())
+		return Release{}, fmt.Errorf("failed to retrieve release by tag from github. %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("failed to get release by tag. http code %d, http body %s", resp.StatusCode, string(responseBody))
-		return Release{}, err
+	if err := httpclient.CheckStatus(resp, responseBody); err != nil {
+		return Release{}, fmt.Errorf("failed to get release by tag. %w", err)
 	}
 
 	var githubRelease Release
@@ -100,3 +186,78 @@ func (github *DefaultClient) GetReleaseByTag(tag string) (Release, error) {
 
 	return githubRelease, nil
 }
+
+func (github *DefaultClient) repository() string {
+	if github.Repository == "" {
+		return DefaultRepository
+	}
+	return github.Repository
+}
+
+// ValidateRepository checks that the repository is in owner/name form.
+func ValidateRepository(repository string) error {
+	owner, name, found := strings.Cut(repository, "/")
+	if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid repository [%s]. Expected owner/name", repository)
+	}
+	return nil
+}
+
+// send executes the request and reads the response body. The Authorization header is only
+// added when an API key is set. If the rate limit is exceeded send waits for the reset
+// (at most MaxRateLimitWait) and repeats the request once.
+func (github *DefaultClient) send(req *http.Request) (*http.Response, []byte, error) {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if github.ApiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", github.ApiKey))
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := github.Client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read response. %s", err.Error())
+		}
+
+		rateLimit := parseRateLimit(resp.Header)
+		if rateLimit.Known {
+			slog.Debug("GitHub api rate limit", "remaining", rateLimit.Remaining, "limit", rateLimit.Limit, "reset", rateLimit.Reset)
+		}
+
+		if !isRateLimited(resp, rateLimit) {
+			return resp, body, nil
+		}
+
+		wait := rateLimitWait(resp, rateLimit, time.Now())
+		if attempt > 0 || wait > github.MaxRateLimitWait {
+			return nil, nil, &RateLimitError{RateLimit: rateLimit, Authorized: github.ApiKey != "", Wait: wait}
+		}
+
+		slog.Warn("GitHub api rate limit exceeded. Waiting for reset", "wait", wait.Round(time.Second))
+		if err := github.wait(req.Context(), wait); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// wait returns early with an error if ctx is cancelled.
+func (github *DefaultClient) wait(ctx context.Context, duration time.Duration) error {
+	if github.sleep != nil {
+		github.sleep(duration)
+		return nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -1,12 +1,18 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"n1kit0s/vt-manager/app/httpclient"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetReleases(t *testing.T) {
@@ -15,36 +21,45 @@ func TestGetReleases(t *testing.T) {
 
 	githubClient := createGithubClient(server)
 
-	releases, err := githubClient.GetReleases()
+	releases, err := githubClient.GetReleases(context.Background())
 	if err != nil {
 		t.Error(err.Error())
 	}
 
 	expectedReleases := []Release{
 		{
-			TagName: "v2.3.0",
+			TagName:     "v2.3.0",
+			Name:        "v2.3.0",
+			PublishedAt: mustParseTime(t, "2023-11-29T07:28:55Z"),
 			Assets: []Asset{
 				{
 					Name:        "vuetorrent.zip",
 					DownloadUrl: "https://github.com/WDaan/VueTorrent/releases/download/v2.3.0/vuetorrent.zip",
+					Size:        3190859,
 				},
 			},
 		},
 		{
-			TagName: "v2.2.0",
+			TagName:     "v2.2.0",
+			Name:        "v2.2.0",
+			PublishedAt: mustParseTime(t, "2023-11-20T21:44:28Z"),
 			Assets: []Asset{
 				{
 					Name:        "vuetorrent.zip",
 					DownloadUrl: "https://github.com/WDaan/VueTorrent/releases/download/v2.2.0/vuetorrent.zip",
+					Size:        3188575,
 				},
 			},
 		},
 		{
-			TagName: "v2.1.1",
+			TagName:     "v2.1.1",
+			Name:        "v2.1.1",
+			PublishedAt: mustParseTime(t, "2023-11-11T10:44:40Z"),
 			Assets: []Asset{
 				{
 					Name:        "vuetorrent.zip",
 					DownloadUrl: "https://github.com/WDaan/VueTorrent/releases/download/v2.1.1/vuetorrent.zip",
+					Size:        3169685,
 				},
 			},
 		},
@@ -63,17 +78,20 @@ func TestGetReleaseByTag(t *testing.T) {
 
 	githubClient := createGithubClient(server)
 
-	receivedRelease, err := githubClient.GetReleaseByTag("v2.3.0")
+	receivedRelease, err := githubClient.GetReleaseByTag(context.Background(), "v2.3.0")
 	if err != nil {
 		t.Error(err.Error())
 	}
 
 	expectedRelease := Release{
-		TagName: "v2.3.0",
+		TagName:     "v2.3.0",
+		Name:        "v2.3.0",
+		PublishedAt: mustParseTime(t, "2023-11-29T07:28:55Z"),
 		Assets: []Asset{
 			{
 				Name:        "vuetorrent.zip",
 				DownloadUrl: "https://github.com/WDaan/VueTorrent/releases/download/v2.3.0/vuetorrent.zip",
+				Size:        3190859,
 			},
 		},
 	}
@@ -113,3 +131,145 @@ func readFileContent(t *testing.T, filePath string) []byte {
 
 	return bytes
 }
+
+func TestGetReleasesFollowsNextPage(t *testing.T) {
+	// Setup
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("per_page is not passed. Query: %s", r.URL.RawQuery)
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/WDaan/VueTorrent/releases?per_page=2&page=2>; rel="next", <%s/repos/WDaan/VueTorrent/releases?per_page=2&page=2>; rel="last"`, server.URL, server.URL))
+			w.Write([]byte(`[{"tag_name": "v2.3.0"}, {"tag_name": "v2.2.0"}]`))
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/WDaan/VueTorrent/releases?per_page=2&page=1>; rel="prev", <%s/repos/WDaan/VueTorrent/releases?per_page=2&page=1>; rel="first"`, server.URL, server.URL))
+			w.Write([]byte(`[{"tag_name": "v2.1.1"}]`))
+		default:
+			t.Errorf("Unexpected page requested: %s", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	githubClient := &DefaultClient{
+		ApiKey:  "foo",
+		Client:  server.Client(),
+		BaseUrl: server.URL,
+		PerPage: 2,
+	}
+
+	// Run
+	releases, err := githubClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expectedTags := []string{"v2.3.0", "v2.2.0", "v2.1.1"}
+	if len(releases) != len(expectedTags) {
+		t.Fatalf("Expected %d releases. Actual: %d", len(expectedTags), len(releases))
+	}
+	for i, release := range releases {
+		if release.TagName != expectedTags[i] {
+			t.Errorf("Expected tag %s. Actual: %s", expectedTags[i], release.TagName)
+		}
+	}
+}
+
+func TestGetReleasesStopsAtMaxPages(t *testing.T) {
+	// Setup
+	var requests = 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Link", fmt.Sprintf(`</repos/WDaan/VueTorrent/releases?page=%d>; rel="next"`, requests+1))
+		w.Write([]byte(fmt.Sprintf(`[{"tag_name": "v0.0.%d"}]`, requests)))
+	}))
+	defer server.Close()
+
+	githubClient := &DefaultClient{
+		ApiKey:   "foo",
+		Client:   server.Client(),
+		BaseUrl:  server.URL,
+		MaxPages: 3,
+	}
+
+	// Run
+	releases, err := githubClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if requests != 3 || len(releases) != 3 {
+		t.Errorf("Expected 3 pages to be fetched. Requests: %d, releases: %d", requests, len(releases))
+	}
+}
+
+func TestGetReleasesFailsOnErrorStatus(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "Bad credentials"}`))
+	}))
+	defer server.Close()
+
+	githubClient := createGithubClient(server)
+
+	// Run
+	_, err := githubClient.GetReleases(context.Background())
+	if !errors.Is(err, httpclient.ErrUnauthorized) {
+		t.Fatalf("Expected unauthorized error. Actual: %v", err)
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return parsed
+}
+
+func TestClientUsesConfiguredRepositoryAndBaseUrl(t *testing.T) {
+	// Setup
+	var requestedPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+		if strings.Contains(r.URL.Path, "/tags/") {
+			w.Write([]byte(`{"tag_name": "v2.3.0"}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	githubClient := NewClient(Config{BaseUrl: server.URL + "/api/v3/", Repository: "fork/VueTorrent"})
+	githubClient.(*DefaultClient).Client = server.Client()
+
+	// Run
+	if _, err := githubClient.GetReleases(context.Background()); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := githubClient.GetReleaseByTag(context.Background(), "v2.3.0"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expectedPaths := []string{"/api/v3/repos/fork/VueTorrent/releases", "/api/v3/repos/fork/VueTorrent/releases/tags/v2.3.0"}
+	if !reflect.DeepEqual(requestedPaths, expectedPaths) {
+		t.Errorf("\nGot: %v \nExp: %v", requestedPaths, expectedPaths)
+	}
+}
+
+func TestValidateRepository(t *testing.T) {
+	for _, repository := range []string{"VueTorrent/VueTorrent", "WDaan/VueTorrent"} {
+		if err := ValidateRepository(repository); err != nil {
+			t.Errorf("Repository [%s] should be valid. Error: %s", repository, err.Error())
+		}
+	}
+
+	for _, repository := range []string{"", "VueTorrent", "/VueTorrent", "VueTorrent/", "a/b/c"} {
+		if err := ValidateRepository(repository); err == nil {
+			t.Errorf("Repository [%s] should be invalid", repository)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"fmt"
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+	// Known is false if the response had no rate limit headers
+	Known bool
+}
+
+type RateLimitError struct {
+	RateLimit  RateLimit
+	Authorized bool
+	Wait       time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	var message = fmt.Sprintf("github api rate limit exceeded, resets in %s", e.Wait.Round(time.Second))
+	if !e.RateLimit.Reset.IsZero() {
+		message = fmt.Sprintf("%s (at %s)", message, e.RateLimit.Reset.Format(time.RFC3339))
+	}
+	if !e.Authorized {
+		message += ". Anonymous requests have a low limit, set a GitHub API key to raise it"
+	}
+	return message
+}
+
+// Is makes RateLimitError match httpclient.ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == httpclient.ErrRateLimited
+}
+
+func parseRateLimit(header http.Header) RateLimit {
+	var rateLimit RateLimit
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rateLimit
+	}
+	rateLimit.Remaining = remaining
+	rateLimit.Known = true
+
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		rateLimit.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(reset, 0)
+	}
+
+	return rateLimit
+}
+
+// isRateLimited reports whether the response was rejected because of the primary
+// (X-RateLimit-Remaining is 0) or secondary (Retry-After) rate limit.
+func isRateLimited(resp *http.Response, rateLimit RateLimit) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	return (rateLimit.Known && rateLimit.Remaining == 0) || resp.Header.Get("Retry-After") != ""
+}
+
+// rateLimitWait returns how long to wait before the request can be repeated.
+func rateLimitWait(resp *http.Response, rateLimit RateLimit, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if rateLimit.Reset.IsZero() || !rateLimit.Reset.After(now) {
+		return 0
+	}
+	return rateLimit.Reset.Sub(now)
+}
@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymousRequestHasNoAuthorizationHeader(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Header["Authorization"]; ok {
+			t.Errorf("Authorization header should not be sent. Actual: %s", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	githubClient := &DefaultClient{
+		Client:  server.Client(),
+		BaseUrl: server.URL,
+	}
+
+	// Run
+	_, err := githubClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestAuthorizedRequestHasAuthorizationHeader(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer foo" {
+			t.Errorf("Unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	githubClient := createGithubClient(server)
+
+	// Run
+	_, err := githubClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestWaitsForRateLimitReset(t *testing.T) {
+	// Setup
+	var requests = 0
+	reset := time.Now().Add(30 * time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-RateLimit-Limit", "60")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		w.Write([]byte(`[{"tag_name": "v2.3.0"}]`))
+	}))
+	defer server.Close()
+
+	var waited time.Duration
+	githubClient := &DefaultClient{
+		Client:           server.Client(),
+		BaseUrl:          server.URL,
+		MaxRateLimitWait: time.Minute,
+		sleep:            func(d time.Duration) { waited = d },
+	}
+
+	// Run
+	releases, err := githubClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(releases) != 1 || requests != 2 {
+		t.Errorf("Request should be repeated after reset. Requests: %d, releases: %d", requests, len(releases))
+	}
+	if waited <= 0 || waited > 30*time.Second {
+		t.Errorf("Unexpected wait time: %s", waited)
+	}
+}
+
+func TestFailsWhenRateLimitResetIsTooFar(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	githubClient := &DefaultClient{
+		Client:           server.Client(),
+		BaseUrl:          server.URL,
+		MaxRateLimitWait: time.Minute,
+		sleep:            func(d time.Duration) { t.Errorf("Should not wait %s", d) },
+	}
+
+	// Run
+	_, err := githubClient.GetReleaseByTag(context.Background(), "v2.3.0")
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected rate limit error. Actual: %v", err)
+	}
+	if !strings.Contains(err.Error(), "set a GitHub API key") {
+		t.Errorf("Anonymous rate limit error should suggest an API key. Actual: %s", err.Error())
+	}
+}
+
+func TestRetryAfterIsHonoured(t *testing.T) {
+	// Setup
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "15")
+	rateLimit := parseRateLimit(resp.Header)
+
+	// Run
+	if !isRateLimited(resp, rateLimit) {
+		t.Fatal("Response with Retry-After should be rate limited")
+	}
+
+	wait := rateLimitWait(resp, rateLimit, time.Now())
+	if wait != 15*time.Second {
+		t.Errorf("Expected 15s wait. Actual: %s", wait)
+	}
+}
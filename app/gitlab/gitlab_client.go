@@ -0,0 +1,158 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Link is a release asset. GitLab releases only reference files through links.
+type Link struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+	// DirectAssetUrl is a permanent url of the link. Older GitLab versions don't return it.
+	DirectAssetUrl string `json:"direct_asset_url"`
+	LinkType       string `json:"link_type"`
+}
+
+type Assets struct {
+	Links []Link `json:"links"`
+}
+
+type Release struct {
+	TagName    string    `json:"tag_name"`
+	Name       string    `json:"name"`
+	ReleasedAt time.Time `json:"released_at"`
+	// UpcomingRelease is set for releases with a release date in the future.
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          Assets `json:"assets"`
+}
+
+type Client interface {
+	GetReleases(ctx context.Context) ([]Release, error)
+	GetReleaseByTag(ctx context.Context, tag string) (Release, error)
+}
+
+const DefaultBaseUrl = "https://gitlab.com"
+
+type Config struct {
+	// BaseUrl is the instance url. Empty means DefaultBaseUrl.
+	BaseUrl string
+	// Repository is the project path, e.g. group/VueTorrent. Subgroups are allowed.
+	Repository string
+	// ApiKey is an optional personal, project or group access token.
+	ApiKey string
+	// PerPage is the page size requested from the releases endpoint. Zero means GitLab's default.
+	PerPage int
+	// MaxPages limits how many pages GetReleases requests. Zero means no limit.
+	MaxPages int
+	// Transport executes the requests, e.g. a caching one. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+type DefaultClient struct {
+	ApiKey     string
+	Client     *http.Client
+	BaseUrl    string
+	Repository string
+	PerPage    int
+	MaxPages   int
+}
+
+func NewClient(config Config) Client {
+	var baseUrl = strings.TrimSuffix(config.BaseUrl, "/")
+	if baseUrl == "" {
+		baseUrl = DefaultBaseUrl
+	}
+
+	return &DefaultClient{
+		ApiKey:     config.ApiKey,
+		Client:     &http.Client{Transport: config.Transport},
+		BaseUrl:    baseUrl,
+		Repository: config.Repository,
+		PerPage:    config.PerPage,
+		MaxPages:   config.MaxPages,
+	}
+}
+
+// GetReleases follows the X-Next-Page header until the last page or MaxPages is reached.
+func (gitlab *DefaultClient) GetReleases(ctx context.Context) ([]Release, error) {
+	var releases = []Release{}
+	for page := "1"; page != ""; {
+		pageNumber, _ := strconv.Atoi(page)
+		if gitlab.MaxPages > 0 && pageNumber > gitlab.MaxPages {
+			break
+		}
+
+		var query = url.Values{"page": {page}}
+		if gitlab.PerPage > 0 {
+			query.Set("per_page", strconv.Itoa(gitlab.PerPage))
+		}
+
+		var pageReleases []Release
+		resp, err := gitlab.get(ctx, fmt.Sprintf("%s/releases?%s", gitlab.projectUrl(), query.Encode()), &pageReleases)
+		if err != nil {
+			return []Release{}, fmt.Errorf("failed to get releases. %w", err)
+		}
+
+		releases = append(releases, pageReleases...)
+		page = resp.Header.Get("X-Next-Page")
+	}
+
+	return releases, nil
+}
+
+func (gitlab *DefaultClient) GetReleaseByTag(ctx context.Context, tag string) (Release, error) {
+	var releaseUrl = fmt.Sprintf("%s/releases/%s", gitlab.projectUrl(), url.PathEscape(tag))
+
+	var release Release
+	if _, err := gitlab.get(ctx, releaseUrl, &release); err != nil {
+		return Release{}, fmt.Errorf("failed to get release by tag. %w", err)
+	}
+
+	return release, nil
+}
+
+// projectUrl addresses the project by its url-encoded path, so no project id lookup is needed.
+func (gitlab *DefaultClient) projectUrl() string {
+	return fmt.Sprintf("%s/api/v4/projects/%s", gitlab.BaseUrl, url.PathEscape(gitlab.Repository))
+}
+
+func (gitlab *DefaultClient) get(ctx context.Context, requestUrl string, result any) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if gitlab.ApiKey != "" {
+		req.Header.Set("PRIVATE-TOKEN", gitlab.ApiKey)
+	}
+
+	resp, err := gitlab.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response. %s", err.Error())
+	}
+
+	if err := httpclient.CheckStatus(resp, body); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: [%s]. %s", string(body), err.Error())
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,172 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetReleases(t *testing.T) {
+	// Setup
+	server := mockServerWithResponse(t, "/api/v4/projects/tools%2Fmirrors%2FVueTorrent/releases", "testdata/releases.json")
+	defer server.Close()
+
+	gitlabClient := createGitlabClient(server)
+
+	// Run
+	releases, err := gitlabClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expectedReleases := []Release{
+		{
+			TagName:    "v2.3.0",
+			Name:       "v2.3.0",
+			ReleasedAt: mustParseTime(t, "2023-11-29T07:28:55Z"),
+			Assets: Assets{Links: []Link{
+				{
+					Name:           "vuetorrent.zip",
+					Url:            "https://gitlab.example.com/api/v4/projects/42/packages/generic/vuetorrent/2.3.0/vuetorrent.zip",
+					DirectAssetUrl: "https://gitlab.example.com/tools/mirrors/VueTorrent/-/releases/v2.3.0/downloads/vuetorrent.zip",
+					LinkType:       "package",
+				},
+				{
+					Name:           "SHA256SUMS",
+					Url:            "https://gitlab.example.com/api/v4/projects/42/packages/generic/vuetorrent/2.3.0/SHA256SUMS",
+					DirectAssetUrl: "https://gitlab.example.com/tools/mirrors/VueTorrent/-/releases/v2.3.0/downloads/SHA256SUMS",
+					LinkType:       "other",
+				},
+			}},
+		},
+		{
+			TagName:    "v2.2.0",
+			Name:       "v2.2.0",
+			ReleasedAt: mustParseTime(t, "2023-11-20T21:44:28Z"),
+			Assets: Assets{Links: []Link{
+				{
+					Name:     "vuetorrent.zip",
+					Url:      "https://gitlab.example.com/api/v4/projects/42/packages/generic/vuetorrent/2.2.0/vuetorrent.zip",
+					LinkType: "package",
+				},
+			}},
+		},
+	}
+
+	if !reflect.DeepEqual(releases, expectedReleases) {
+		t.Errorf("\nGot: %+v \nExp: %+v", releases, expectedReleases)
+	}
+}
+
+func TestGetReleaseByTag(t *testing.T) {
+	// Setup
+	server := mockServerWithResponse(t, "/api/v4/projects/tools%2Fmirrors%2FVueTorrent/releases/v2.3.0", "testdata/release_by_tag.json")
+	defer server.Close()
+
+	gitlabClient := createGitlabClient(server)
+
+	// Run
+	release, err := gitlabClient.GetReleaseByTag(context.Background(), "v2.3.0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if release.TagName != "v2.3.0" || len(release.Assets.Links) != 2 {
+		t.Errorf("Unexpected release %+v", release)
+	}
+}
+
+func TestGetReleasesFollowsNextPage(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "foo" {
+			t.Errorf("Unexpected PRIVATE-TOKEN header [%s]", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		if r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("per_page is not passed. Query: %s", r.URL.RawQuery)
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`[{"tag_name": "v2.3.0"}, {"tag_name": "v2.2.0"}]`))
+		case "2":
+			w.Header().Set("X-Next-Page", "")
+			w.Write([]byte(`[{"tag_name": "v2.1.1"}]`))
+		default:
+			t.Errorf("Unexpected page requested: %s", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	gitlabClient := &DefaultClient{ApiKey: "foo", Client: server.Client(), BaseUrl: server.URL, Repository: "tools/VueTorrent", PerPage: 2}
+
+	// Run
+	releases, err := gitlabClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(releases) != 3 || releases[2].TagName != "v2.1.1" {
+		t.Fatalf("Unexpected releases %+v", releases)
+	}
+}
+
+func TestGetReleasesStopsAtMaxPages(t *testing.T) {
+	// Setup
+	var requests = 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Next-Page", fmt.Sprintf("%d", requests+1))
+		w.Write([]byte(fmt.Sprintf(`[{"tag_name": "v0.0.%d"}]`, requests)))
+	}))
+	defer server.Close()
+
+	gitlabClient := &DefaultClient{Client: server.Client(), BaseUrl: server.URL, Repository: "tools/VueTorrent", MaxPages: 3}
+
+	// Run
+	releases, err := gitlabClient.GetReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if requests != 3 || len(releases) != 3 {
+		t.Fatalf("Expected 3 requests and releases. Actual: %d requests, %d releases", requests, len(releases))
+	}
+}
+
+func mockServerWithResponse(t *testing.T, expectedPath string, fileWithResponse string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != expectedPath {
+			t.Errorf("Unexpected path %s. Expected %s", r.URL.EscapedPath(), expectedPath)
+		}
+
+		content, err := os.ReadFile(fileWithResponse)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		w.Write(content)
+	}))
+}
+
+func createGitlabClient(server *httptest.Server) Client {
+	return &DefaultClient{
+		ApiKey:     "foo",
+		Client:     server.Client(),
+		BaseUrl:    server.URL,
+		Repository: "tools/mirrors/VueTorrent",
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return parsed
+}
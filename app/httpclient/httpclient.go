@@ -0,0 +1,183 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"n1kit0s/vt-manager/app/schedule"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultConnectTimeout = 10 * time.Second
+	DefaultReadTimeout    = 30 * time.Second
+	// maxRetryDelay caps the exponential backoff between retries.
+	maxRetryDelay = 30 * time.Second
+)
+
+type Config struct {
+	// ConnectTimeout limits establishing a connection including the TLS handshake. Zero means DefaultConnectTimeout.
+	ConnectTimeout time.Duration
+	// ReadTimeout is the longest wait for the response headers or the next part of the body.
+	// Zero means DefaultReadTimeout.
+	ReadTimeout time.Duration
+	// Retries is the number of times an idempotent request is repeated after a network error or 5xx response.
+	Retries int
+	// RetryDelay is the delay before the first retry. It doubles with every next one.
+	RetryDelay time.Duration
+	// Proxy is the url of the proxy for all requests except hosts in NO_PROXY.
+	// Empty means HTTPS_PROXY and HTTP_PROXY are used.
+	Proxy string
+	// CAFile contains PEM certificates trusted in addition to the system ones.
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key for mutual TLS.
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify disables verification of server certificates.
+	InsecureSkipVerify bool
+}
+
+// NewClient returns a client using NewTransport.
+func NewClient(config Config) (*http.Client, error) {
+	transport, err := NewTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// NewTransport returns a transport with connect and read timeouts that retries failed idempotent requests.
+// It fails if the proxy url, CA file or client certificate is invalid.
+func NewTransport(config Config) (http.RoundTripper, error) {
+	var connectTimeout = config.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+	var readTimeout = config.ReadTimeout
+	if readTimeout <= 0 {
+		readTimeout = DefaultReadTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = readTimeout
+
+	proxy, err := proxyFunc(config.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+
+	tlsClientConfig, err := tlsConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsClientConfig != nil {
+		transport.TLSClientConfig = tlsClientConfig
+	}
+
+	return &retryTransport{
+		base:    &readTimeoutTransport{base: transport, timeout: readTimeout},
+		retries: config.Retries,
+		delay:   config.RetryDelay,
+	}, nil
+}
+
+// retryTransport repeats GET and HEAD requests failed with a network error or a 5xx status.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	delay   time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.retries <= 0 || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return t.base.RoundTrip(req)
+	}
+
+	backoff := schedule.Backoff{Initial: t.delay, Max: maxRetryDelay}
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if !isRetryable(resp, err) || attempt > t.retries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = fmt.Sprintf("http code %d", resp.StatusCode)
+			// Reading the rest of the body lets the connection be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+
+		delay := backoff.Next()
+		slog.Warn("Request failed. Retrying", "url", req.URL.Redacted(), "reason", reason, "attempt", attempt, "retryIn", delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// readTimeoutTransport cancels the request if no part of the body arrives within timeout.
+// Unlike http.Client.Timeout it doesn't limit the duration of a slow but progressing download.
+type readTimeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *readTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The timer only runs during Read, a slow reader (e.g. limited rate) is not a timeout
+	timer := time.AfterFunc(t.timeout, cancel)
+	timer.Stop()
+
+	resp.Body = &readTimeoutBody{body: resp.Body, timeout: t.timeout, cancel: cancel, timer: timer}
+	return resp, nil
+}
+
+type readTimeoutBody struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	cancel  context.CancelFunc
+	timer   *time.Timer
+}
+
+func (b *readTimeoutBody) Read(buffer []byte) (int, error) {
+	b.timer.Reset(b.timeout)
+	n, err := b.body.Read(buffer)
+	if !b.timer.Stop() && err != nil && err != io.EOF {
+		return n, fmt.Errorf("no data received for %s", b.timeout)
+	}
+	return n, err
+}
+
+func (b *readTimeoutBody) Close() error {
+	b.timer.Stop()
+	err := b.body.Close()
+	b.cancel()
+	return err
+}
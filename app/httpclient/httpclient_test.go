@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetries(t *testing.T) {
+	tests := map[string]struct {
+		method           string
+		statuses         []int
+		expectedStatus   int
+		expectedRequests int
+	}{
+		"server error then success": {
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 3,
+		},
+		"gives up after retries": {
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			expectedStatus:   http.StatusBadGateway,
+			expectedRequests: 3,
+		},
+		"client error is not retried": {
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusNotFound, http.StatusOK},
+			expectedStatus:   http.StatusNotFound,
+			expectedRequests: 1,
+		},
+		"not idempotent request is not retried": {
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusBadGateway, http.StatusOK},
+			expectedStatus:   http.StatusBadGateway,
+			expectedRequests: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statuses[requests])
+				requests++
+			}))
+			defer server.Close()
+
+			client, _ := NewClient(Config{Retries: 2, RetryDelay: time.Millisecond})
+			req, _ := http.NewRequest(test.method, server.URL, nil)
+
+			// Run
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Errorf("Expected status %d. Actual: %d", test.expectedStatus, resp.StatusCode)
+			}
+			if requests != test.expectedRequests {
+				t.Errorf("Expected %d requests. Actual: %d", test.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestRetriesNetworkErrors(t *testing.T) {
+	// Setup
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			// Drop the connection without a response
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(Config{Retries: 1, RetryDelay: time.Millisecond})
+
+	// Run
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests. Actual: %d", requests)
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	// Setup
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("first part"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, _ := NewClient(Config{ReadTimeout: 50 * time.Millisecond})
+
+	// Run
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resp.Body.Close()
+
+	// A slow reader must not time out
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = io.ReadAll(resp.Body)
+	if err == nil || !strings.Contains(err.Error(), "no data received") {
+		t.Errorf("Expected read timeout. Actual: %v", err)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	tests := map[string]struct {
+		statusCode    int
+		expectedError error
+	}{
+		"ok":           {statusCode: http.StatusOK},
+		"partial":      {statusCode: http.StatusPartialContent},
+		"not found":    {statusCode: http.StatusNotFound, expectedError: ErrNotFound},
+		"unauthorized": {statusCode: http.StatusUnauthorized, expectedError: ErrUnauthorized},
+		"forbidden":    {statusCode: http.StatusForbidden, expectedError: ErrUnauthorized},
+		"rate limited": {statusCode: http.StatusTooManyRequests, expectedError: ErrRateLimited},
+		"server error": {statusCode: http.StatusBadGateway, expectedError: ErrServerError},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Run
+			err := CheckStatus(&http.Response{StatusCode: test.statusCode}, []byte("<html>error</html>"))
+
+			if test.expectedError == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+				return
+			}
+			if !errors.Is(err, test.expectedError) {
+				t.Errorf("Expected %v. Actual: %v", test.expectedError, err)
+			}
+			for _, other := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrServerError} {
+				if other != test.expectedError && errors.Is(err, other) {
+					t.Errorf("Error %v must not match %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckStatusTruncatesBody(t *testing.T) {
+	// Run
+	err := CheckStatus(&http.Response{StatusCode: http.StatusBadGateway}, []byte(strings.Repeat("x", 2000)))
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || len(statusErr.Body) != maxErrorBody+3 {
+		t.Errorf("Body was not truncated. Error: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// proxyFunc returns the proxy selection of the transport. Without proxyUrl, HTTPS_PROXY, HTTP_PROXY
+// and NO_PROXY are used. An explicit proxyUrl is used for every request except local hosts (e.g. qBittorrent
+// on localhost) and hosts listed in NO_PROXY.
+func proxyFunc(proxyUrl string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyUrl == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	parsedUrl, err := url.Parse(proxyUrl)
+	if err != nil || parsedUrl.Host == "" {
+		// A bare host:port is parsed as a scheme and opaque path
+		parsedUrl, err = url.Parse("http://" + proxyUrl)
+	}
+	if err != nil || parsedUrl.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url [%s]", proxyUrl)
+	}
+	switch parsedUrl.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme [%s]. expected http, https or socks5", parsedUrl.Scheme)
+	}
+
+	noProxy := parseNoProxy(getEnv("NO_PROXY", "no_proxy"))
+	return func(req *http.Request) (*url.URL, error) {
+		if isLocalHost(req.URL.Hostname()) || noProxy.match(req.URL) {
+			return nil, nil
+		}
+		return parsedUrl, nil
+	}, nil
+}
+
+// isLocalHost reports whether host is never proxied, like http.ProxyFromEnvironment does.
+func isLocalHost(host string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+func getEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// noProxyEntry is a single NO_PROXY item: a domain, an IP or a CIDR range, optionally with a port.
+type noProxyEntry struct {
+	domain string
+	ipNet  *net.IPNet
+	ip     net.IP
+	port   string
+}
+
+type noProxyList []noProxyEntry
+
+// parseNoProxy reads a comma separated NO_PROXY value. "*" matches every host, a domain
+// matches itself and its subdomains, a leading dot (".example.com") only subdomains.
+func parseNoProxy(value string) noProxyList {
+	var list noProxyList
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+
+		if _, ipNet, err := net.ParseCIDR(item); err == nil {
+			list = append(list, noProxyEntry{ipNet: ipNet})
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(item)
+		if err != nil {
+			host, port = item, ""
+		}
+		if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+			list = append(list, noProxyEntry{ip: ip, port: port})
+			continue
+		}
+
+		if strings.HasPrefix(host, "*.") {
+			host = host[1:]
+		}
+		list = append(list, noProxyEntry{domain: host, port: port})
+	}
+	return list
+}
+
+func (l noProxyList) match(requestUrl *url.URL) bool {
+	host := strings.ToLower(requestUrl.Hostname())
+	port := requestUrl.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[requestUrl.Scheme]
+	}
+	ip := net.ParseIP(host)
+
+	for _, entry := range l {
+		if entry.port != "" && entry.port != port {
+			continue
+		}
+
+		switch {
+		case entry.ipNet != nil:
+			if ip != nil && entry.ipNet.Contains(ip) {
+				return true
+			}
+		case entry.ip != nil:
+			if ip != nil && entry.ip.Equal(ip) {
+				return true
+			}
+		case entry.domain == "*":
+			return true
+		case strings.HasPrefix(entry.domain, "."):
+			if strings.HasSuffix(host, entry.domain) {
+				return true
+			}
+		default:
+			if host == entry.domain || strings.HasSuffix(host, "."+entry.domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
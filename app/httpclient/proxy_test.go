@@ -0,0 +1,141 @@
+package httpclient
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNoProxyMatch(t *testing.T) {
+	tests := map[string]struct {
+		noProxy  string
+		url      string
+		expected bool
+	}{
+		"empty":                  {noProxy: "", url: "https://api.github.com", expected: false},
+		"wildcard":               {noProxy: "*", url: "https://api.github.com", expected: true},
+		"exact host":             {noProxy: "api.github.com", url: "https://api.github.com/repos", expected: true},
+		"subdomain":              {noProxy: "github.com", url: "https://api.github.com", expected: true},
+		"leading dot":            {noProxy: ".github.com", url: "https://github.com", expected: false},
+		"other domain":           {noProxy: "github.com", url: "https://notgithub.com", expected: false},
+		"list with spaces":       {noProxy: "localhost, .internal", url: "http://git.internal", expected: true},
+		"matching port":          {noProxy: "git.lan:8443", url: "https://git.lan:8443", expected: true},
+		"other port":             {noProxy: "git.lan:8443", url: "https://git.lan", expected: false},
+		"ip":                     {noProxy: "10.0.0.5", url: "http://10.0.0.5:3000", expected: true},
+		"cidr":                   {noProxy: "192.168.0.0/16", url: "http://192.168.1.10", expected: true},
+		"ip outside of cidr":     {noProxy: "192.168.0.0/16", url: "http://10.0.0.1", expected: false},
+		"case insensitive":       {noProxy: "GitHub.com", url: "https://API.github.com", expected: true},
+		"wildcard subdomain":     {noProxy: "*.example.com", url: "https://a.example.com", expected: true},
+		"wildcard not apex host": {noProxy: "*.example.com", url: "https://example.com", expected: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			requestUrl, _ := url.Parse(test.url)
+
+			// Run
+			actual := parseNoProxy(test.noProxy).match(requestUrl)
+
+			if actual != test.expected {
+				t.Errorf("Expected %t for [%s] in [%s]. Actual: %t", test.expected, test.url, test.noProxy, actual)
+			}
+		})
+	}
+}
+
+func TestProxy(t *testing.T) {
+	// Setup
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("proxied " + r.URL.Host))
+	}))
+	defer proxy.Close()
+
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("direct"))
+	}))
+	defer direct.Close()
+
+	t.Setenv("NO_PROXY", "127.0.0.1")
+	client, err := NewClient(Config{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := map[string]struct {
+		url      string
+		expected string
+	}{
+		"through proxy":    {url: "http://vuetorrent.example/releases", expected: "proxied vuetorrent.example"},
+		"host in NO_PROXY": {url: direct.URL, expected: "direct"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Run
+			resp, err := client.Get(test.url)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != test.expected {
+				t.Errorf("Expected [%s]. Actual: [%s]", test.expected, string(body))
+			}
+		})
+	}
+}
+
+func TestProxySkipsLocalHosts(t *testing.T) {
+	// Setup
+	var proxied int
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("direct"))
+	}))
+	defer direct.Close()
+
+	t.Setenv("NO_PROXY", "")
+	t.Setenv("no_proxy", "")
+	client, err := NewClient(Config{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(direct.URL, "http://"))
+	for _, requestUrl := range []string{direct.URL, "http://localhost:" + port} {
+		// Run
+		resp, err := client.Get(requestUrl)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != "direct" {
+			t.Errorf("Expected direct request to %s. Actual: [%s]", requestUrl, string(body))
+		}
+	}
+	if proxied != 0 {
+		t.Errorf("Expected no proxied requests. Actual: %d", proxied)
+	}
+}
+
+func TestInvalidProxy(t *testing.T) {
+	// Run
+	_, err := NewClient(Config{Proxy: "ftp://proxy.lan:21"})
+
+	if err == nil {
+		t.Error("Expected unsupported proxy scheme to fail")
+	}
+}
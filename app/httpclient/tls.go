@@ -0,0 +1,63 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// tlsConfig returns nil if the default TLS settings apply.
+func tlsConfig(config Config) (*tls.Config, error) {
+	if config.CAFile == "" && config.CertFile == "" && config.KeyFile == "" && !config.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	var result = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.CAFile != "" {
+		pool, err := certPool(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		result.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %s. %s", config.CertFile, err.Error())
+		}
+		result.Certificates = []tls.Certificate{cert}
+	}
+
+	if config.InsecureSkipVerify {
+		slog.Warn("TLS certificate verification is disabled")
+		result.InsecureSkipVerify = true
+	}
+
+	return result, nil
+}
+
+// certPool adds certificates from caFile to the system ones, so public hosts stay trusted.
+func certPool(caFile string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file %s. %s", caFile, err.Error())
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		slog.Debug("Can't load system certificates", "error", err.Error())
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no PEM certificates found in CA file %s", caFile)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,118 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePem(t *testing.T, blockType string, content []byte) string {
+	filePath := filepath.Join(t.TempDir(), "file.pem")
+	if err := os.WriteFile(filePath, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600); err != nil {
+		t.Fatal(err.Error())
+	}
+	return filePath
+}
+
+func TestCAFile(t *testing.T) {
+	// Setup
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := writePem(t, "CERTIFICATE", server.Certificate().Raw)
+
+	tests := map[string]struct {
+		config        Config
+		expectedError bool
+	}{
+		"untrusted server":  {config: Config{}, expectedError: true},
+		"trusted by CA":     {config: Config{CAFile: caFile}},
+		"skip verification": {config: Config{InsecureSkipVerify: true}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewClient(test.config)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			// Run
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if test.expectedError && err == nil {
+				t.Error("Expected certificate verification to fail")
+			}
+			if !test.expectedError && err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestInvalidCAFile(t *testing.T) {
+	// Setup
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, []byte("not a certificate"), 0644)
+
+	// Run
+	_, err := NewClient(Config{CAFile: caFile})
+
+	if err == nil {
+		t.Error("Expected CA file without certificates to fail")
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	// Setup
+	var peerCertificates int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peerCertificates = len(r.TLS.PeerCertificates)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	// The server certificate doubles as the client one
+	serverCert := server.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(serverCert.PrivateKey)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	certFile := writePem(t, "CERTIFICATE", serverCert.Certificate[0])
+	keyFile := writePem(t, "PRIVATE KEY", key)
+	caFile := writePem(t, "CERTIFICATE", server.Certificate().Raw)
+
+	client, err := NewClient(Config{CAFile: caFile, CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Run
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resp.Body.Close()
+
+	if peerCertificates != 1 {
+		t.Errorf("Client certificate was not sent")
+	}
+}
+
+func TestClientCertificateRequiresKey(t *testing.T) {
+	// Run
+	_, err := NewClient(Config{CertFile: "client.pem"})
+
+	if err == nil {
+		t.Error("Expected certificate without key to fail")
+	}
+}
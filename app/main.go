@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"n1kit0s/vt-manager/app/cmd"
 	"os"
 
@@ -10,17 +11,51 @@ import (
 )
 
 type Opts struct {
-	InstallCmd  cmd.InstallCommand  `command:"install"`
-	InfoCmd     cmd.InfoCommand     `command:"info"`
-	ListCmd     cmd.ListCommand     `command:"list"`
-	RevisionCmd cmd.RevisionCommand `command:"revision"`
+	cmd.GlobalOptions
+
+	InstallCmd  cmd.InstallCommand     `command:"install"`
+	InfoCmd     cmd.InfoCommand        `command:"info"`
+	ListCmd     cmd.ListCommand        `command:"list"`
+	CheckCmd    cmd.CheckCommand       `command:"check"`
+	RevisionCmd cmd.RevisionCommand    `command:"revision"`
+	RollbackCmd cmd.RollbackCommand    `command:"rollback"`
+	BackupsCmd  cmd.BackupsCommand     `command:"backups"`
+	DaemonCmd   cmd.DaemonCommand      `command:"daemon" alias:"watch"`
+	QbtCmd      cmd.QbittorrentCommand `command:"qbt"`
+	BundleCmd   cmd.BundleCommand      `command:"bundle"`
+	CacheCmd    cmd.CacheCommand       `command:"cache"`
 }
 
 func main() {
 	var opts Opts
-	_, err := flags.Parse(&opts)
+	parser := flags.NewParser(&opts, flags.Default)
+	parser.CommandHandler = func(command flags.Commander, args []string) error {
+		configureLogger(opts.Debug)
+		if command, ok := command.(cmd.Command); ok {
+			if err := command.SetGlobalOptions(&opts.GlobalOptions); err != nil {
+				return err
+			}
+		}
+
+		err := command.Execute(args)
+		var exitCodeErr *cmd.ExitCodeError
+		if errors.As(err, &exitCodeErr) {
+			os.Exit(exitCodeErr.Code)
+		}
+		return err
+	}
+
+	_, err := parser.Parse()
 	if err != nil {
 		slog.Error(err.Error())
 		os.Exit(1)
 	}
 }
+
+func configureLogger(debug bool) {
+	var level = slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+}
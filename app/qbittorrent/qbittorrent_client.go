@@ -0,0 +1,165 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultUsername is the WebUI username of a fresh qBittorrent install.
+const DefaultUsername = "admin"
+
+// DefaultTimeout limits a single WebUI API request, so a hanging qBittorrent doesn't block install.
+const DefaultTimeout = 30 * time.Second
+
+type Client interface {
+	Login() error
+	SetPreferences(preferences map[string]any) error
+	// EnableAlternativeWebUI points qBittorrent to the alternative WebUI files in path and enables it.
+	EnableAlternativeWebUI(path string) error
+}
+
+type Config struct {
+	Url string
+	// Username defaults to DefaultUsername if empty.
+	Username string
+	Password string
+	// Transport executes requests. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
+	// Timeout limits a single request. Zero means DefaultTimeout.
+	Timeout time.Duration
+}
+
+type DefaultClient struct {
+	BaseUrl  string
+	Username string
+	Password string
+	Client   *http.Client
+
+	loggedIn bool
+}
+
+func NewClient(config Config) (Client, error) {
+	baseUrl, err := url.Parse(strings.TrimSuffix(config.Url, "/"))
+	if err != nil || baseUrl.Scheme == "" || baseUrl.Host == "" {
+		return nil, fmt.Errorf("invalid qBittorrent url [%s]", config.Url)
+	}
+
+	// Session cookie (SID) returned by login has to be sent with other requests
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var username = config.Username
+	if username == "" {
+		username = DefaultUsername
+	}
+
+	var timeout = config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &DefaultClient{
+		BaseUrl:  baseUrl.String(),
+		Username: username,
+		Password: config.Password,
+		Client:   &http.Client{Jar: jar, Transport: config.Transport, Timeout: timeout},
+	}, nil
+}
+
+func (qbt *DefaultClient) Login() error {
+	form := url.Values{}
+	form.Set("username", qbt.Username)
+	form.Set("password", qbt.Password)
+
+	statusCode, body, err := qbt.post("/api/v2/auth/login", form)
+	if err != nil {
+		return fmt.Errorf("failed to login to qBittorrent. %s", err.Error())
+	}
+
+	switch {
+	case statusCode == http.StatusForbidden:
+		return fmt.Errorf("failed to login to qBittorrent. IP is banned for too many failed login attempts")
+	case statusCode != http.StatusOK:
+		return fmt.Errorf("failed to login to qBittorrent. http code %d, http body %s", statusCode, body)
+	case strings.TrimSpace(body) != "Ok.":
+		return fmt.Errorf("failed to login to qBittorrent. wrong username or password")
+	}
+
+	qbt.loggedIn = true
+	slog.Info("Logged in to qBittorrent", "url", qbt.BaseUrl)
+	return nil
+}
+
+func (qbt *DefaultClient) SetPreferences(preferences map[string]any) error {
+	if !qbt.loggedIn {
+		if err := qbt.Login(); err != nil {
+			return err
+		}
+	}
+
+	preferencesJson, err := json.Marshal(preferences)
+	if err != nil {
+		return fmt.Errorf("failed to encode qBittorrent preferences. %s", err.Error())
+	}
+
+	form := url.Values{}
+	form.Set("json", string(preferencesJson))
+
+	statusCode, body, err := qbt.post("/api/v2/app/setPreferences", form)
+	if err != nil {
+		return fmt.Errorf("failed to set qBittorrent preferences. %s", err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("failed to set qBittorrent preferences. http code %d, http body %s", statusCode, body)
+	}
+
+	return nil
+}
+
+func (qbt *DefaultClient) EnableAlternativeWebUI(path string) error {
+	err := qbt.SetPreferences(map[string]any{
+		"alternative_webui_enabled": true,
+		"alternative_webui_path":    path,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Alternative WebUI enabled in qBittorrent", "path", path)
+	return nil
+}
+
+func (qbt *DefaultClient) post(endpoint string, form url.Values) (int, string, error) {
+	req, err := http.NewRequest("POST", qbt.BaseUrl+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// qBittorrent rejects requests with a foreign Referer/Origin when CSRF protection is on
+	req.Header.Set("Referer", qbt.BaseUrl)
+	req.Header.Set("Origin", fmt.Sprintf("%s://%s", req.URL.Scheme, req.URL.Host))
+
+	resp, err := qbt.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return resp.StatusCode, string(body), nil
+}
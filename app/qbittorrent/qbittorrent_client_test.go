@@ -0,0 +1,143 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type stubQbittorrent struct {
+	t           *testing.T
+	preferences map[string]any
+	logins      int
+}
+
+func (s *stubQbittorrent) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		s.logins++
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Referer") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.FormValue("username") != "admin" || r.FormValue("password") != "adminadmin" {
+			w.Write([]byte("Fails."))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session", Path: "/"})
+		w.Write([]byte("Ok."))
+	})
+	mux.HandleFunc("/api/v2/app/setPreferences", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("SID")
+		if err != nil || cookie.Value != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Forbidden"))
+			return
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("json")), &s.preferences); err != nil {
+			s.t.Errorf("Preferences are not valid json. Error: %s", err.Error())
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	return mux
+}
+
+func createQbittorrentClient(t *testing.T, server *httptest.Server, password string) Client {
+	client, err := NewClient(Config{Url: server.URL + "/", Username: "admin", Password: password})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return client
+}
+
+func TestEnableAlternativeWebUI(t *testing.T) {
+	// Setup
+	stub := &stubQbittorrent{t: t}
+	server := httptest.NewServer(stub.handler())
+	defer server.Close()
+
+	client := createQbittorrentClient(t, server, "adminadmin")
+
+	// Run
+	err := client.EnableAlternativeWebUI("/vuetorrent")
+	if err != nil {
+		t.Fatalf("EnableAlternativeWebUI failed. Error: %s", err.Error())
+	}
+
+	if stub.preferences["alternative_webui_enabled"] != true {
+		t.Errorf("Alternative WebUI was not enabled. Preferences: %v", stub.preferences)
+	}
+	if stub.preferences["alternative_webui_path"] != "/vuetorrent" {
+		t.Errorf("Alternative WebUI path was not set. Preferences: %v", stub.preferences)
+	}
+	if stub.logins != 1 {
+		t.Errorf("Expected 1 login. Actual: %d", stub.logins)
+	}
+}
+
+func TestLoginWithWrongPassword(t *testing.T) {
+	// Setup
+	stub := &stubQbittorrent{t: t}
+	server := httptest.NewServer(stub.handler())
+	defer server.Close()
+
+	client := createQbittorrentClient(t, server, "wrong")
+
+	// Run
+	err := client.EnableAlternativeWebUI("/vuetorrent")
+	if err == nil {
+		t.Fatal("Expected login error")
+	}
+	if stub.preferences != nil {
+		t.Errorf("Preferences should not be changed")
+	}
+}
+
+func TestLoginWhenBanned(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := createQbittorrentClient(t, server, "adminadmin")
+
+	// Run
+	if err := client.Login(); err == nil {
+		t.Fatal("Expected login error")
+	}
+}
+
+func TestLoginTimesOut(t *testing.T) {
+	// Setup
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewClient(Config{Url: server.URL, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Run
+	if err := client.Login(); err == nil {
+		t.Fatal("Expected login to time out")
+	}
+}
+
+func TestNewClientValidatesUrl(t *testing.T) {
+	for _, qbtUrl := range []string{"", "localhost:8080", "://"} {
+		if _, err := NewClient(Config{Url: qbtUrl}); err == nil {
+			t.Errorf("Expected error for url [%s]", qbtUrl)
+		}
+	}
+}
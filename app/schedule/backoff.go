@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff calculates delays between retries of a failing task. The delay doubles after
+// every failure up to Max, and a random jitter spreads retries of several instances.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	failures int
+	random   func() float64
+}
+
+// Next returns the delay before the next retry and increases the failure count.
+func (b *Backoff) Next() time.Duration {
+	delay := b.Initial
+	for i := 0; i < b.failures && (b.Max <= 0 || delay < b.Max); i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	b.failures++
+
+	// Equal jitter: half of the delay is fixed, the other half is random
+	random := rand.Float64
+	if b.random != nil {
+		random = b.random
+	}
+	half := delay / 2
+	return half + time.Duration(random()*float64(delay-half))
+}
+
+func (b *Backoff) Reset() {
+	b.failures = 0
+}
+
+func (b *Backoff) Failures() int {
+	return b.failures
+}
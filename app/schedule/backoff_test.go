@@ -0,0 +1,42 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffGrowsUpToMax(t *testing.T) {
+	// Setup
+	backoff := Backoff{Initial: time.Second, Max: 10 * time.Second, random: func() float64 { return 1 }}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+
+	// Run
+	for i, expectedDelay := range expected {
+		if delay := backoff.Next(); delay != expectedDelay {
+			t.Errorf("Attempt %d. Expected: %s | Actual: %s", i, expectedDelay, delay)
+		}
+	}
+
+	backoff.Reset()
+	if delay := backoff.Next(); delay != time.Second {
+		t.Errorf("Backoff was not reset. Actual: %s", delay)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	// Setup
+	backoff := Backoff{Initial: 8 * time.Second, Max: time.Minute, random: func() float64 { return 0 }}
+
+	// Run
+	if delay := backoff.Next(); delay != 4*time.Second {
+		t.Errorf("Minimal jittered delay should be half of the delay. Actual: %s", delay)
+	}
+
+	backoff = Backoff{Initial: 8 * time.Second, Max: time.Minute}
+	for i := 0; i < 100; i++ {
+		backoff.Reset()
+		if delay := backoff.Next(); delay < 4*time.Second || delay > 8*time.Second {
+			t.Fatalf("Jittered delay out of range: %s", delay)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Schedule interface {
+	// Next returns the first activation time after t.
+	Next(t time.Time) time.Time
+}
+
+// Interval activates every Duration after the previous check.
+type Interval time.Duration
+
+func (i Interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// Cron is a standard 5 field cron expression: minute hour day-of-month month day-of-week.
+// Fields support "*", lists (1,15), ranges (1-5) and steps (*/10, 0-30/5).
+type Cron struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// Cron matches a day if either day-of-month or day-of-week matches, unless one of them
+	// starts with "*" (e.g. "*" or "*/2"). Then both must match, like in Vixie cron.
+	daysRestricted     bool
+	weekdaysRestricted bool
+}
+
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse accepts a cron expression, one of the @hourly/@daily/... aliases or "@every <duration>".
+func Parse(expression string) (Schedule, error) {
+	expression = strings.TrimSpace(expression)
+
+	if every, found := strings.CutPrefix(expression, "@every "); found {
+		duration, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule [%s]. %s", expression, err.Error())
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("invalid schedule [%s]. interval must be positive", expression)
+		}
+		return Interval(duration), nil
+	}
+
+	if alias, found := cronAliases[expression]; found {
+		expression = alias
+	}
+
+	return ParseCron(expression)
+}
+
+func ParseCron(expression string) (Cron, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("invalid cron expression [%s]. expected 5 fields, got %d", expression, len(fields))
+	}
+
+	var cron Cron
+	var err error
+	if cron.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return Cron{}, fmt.Errorf("invalid minute in [%s]. %s", expression, err.Error())
+	}
+	if cron.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return Cron{}, fmt.Errorf("invalid hour in [%s]. %s", expression, err.Error())
+	}
+	if cron.days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return Cron{}, fmt.Errorf("invalid day of month in [%s]. %s", expression, err.Error())
+	}
+	if cron.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return Cron{}, fmt.Errorf("invalid month in [%s]. %s", expression, err.Error())
+	}
+	if cron.weekdays, err = parseCronField(fields[4], 0, 7); err != nil {
+		return Cron{}, fmt.Errorf("invalid day of week in [%s]. %s", expression, err.Error())
+	}
+
+	// Both 0 and 7 mean Sunday
+	if cron.weekdays&(1<<7) != 0 {
+		cron.weekdays |= 1
+	}
+	cron.daysRestricted = !strings.HasPrefix(fields[2], "*")
+	cron.weekdaysRestricted = !strings.HasPrefix(fields[4], "*")
+
+	if cron.Next(time.Now()).IsZero() {
+		return Cron{}, fmt.Errorf("cron expression [%s] never matches", expression)
+	}
+
+	return cron, nil
+}
+
+// parseCronField returns a bit set of values allowed by the field.
+func parseCronField(field string, lowest int, highest int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangeStr, stepStr, hasStep := strings.Cut(part, "/")
+
+		var step = 1
+		if hasStep {
+			parsedStep, err := strconv.Atoi(stepStr)
+			if err != nil || parsedStep <= 0 {
+				return 0, fmt.Errorf("invalid step [%s]", stepStr)
+			}
+			step = parsedStep
+		}
+
+		var from, to int
+		switch {
+		case rangeStr == "*":
+			from, to = lowest, highest
+		case strings.Contains(rangeStr, "-"):
+			fromStr, toStr, _ := strings.Cut(rangeStr, "-")
+			var err error
+			if from, err = strconv.Atoi(fromStr); err != nil {
+				return 0, fmt.Errorf("invalid range [%s]", rangeStr)
+			}
+			if to, err = strconv.Atoi(toStr); err != nil {
+				return 0, fmt.Errorf("invalid range [%s]", rangeStr)
+			}
+		default:
+			value, err := strconv.Atoi(rangeStr)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value [%s]", rangeStr)
+			}
+			from, to = value, value
+			if hasStep {
+				to = highest
+			}
+		}
+
+		if from < lowest || to > highest || from > to {
+			return 0, fmt.Errorf("value [%s] out of range %d-%d", part, lowest, highest)
+		}
+
+		for value := from; value <= to; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func (c Cron) Next(t time.Time) time.Time {
+	// Activation happens at the start of a minute, strictly after t
+	next := t.Truncate(time.Minute).Add(time.Minute)
+
+	// A valid expression matches at least once in 4 years (e.g. Feb 29)
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		if c.months&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !c.matchDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if c.hours&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if c.minutes&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+
+	return time.Time{}
+}
+
+func (c Cron) matchDay(t time.Time) bool {
+	dayMatch := c.days&(1<<uint(t.Day())) != 0
+	weekdayMatch := c.weekdays&(1<<uint(t.Weekday())) != 0
+
+	if c.daysRestricted && c.weekdaysRestricted {
+		return dayMatch || weekdayMatch
+	}
+	return dayMatch && weekdayMatch
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Thursday
+	from := time.Date(2024, time.February, 1, 10, 17, 30, 0, time.UTC)
+
+	tests := map[string]struct {
+		expression string
+		expected   time.Time
+	}{
+		"every minute":         {expression: "* * * * *", expected: time.Date(2024, time.February, 1, 10, 18, 0, 0, time.UTC)},
+		"every 15 minutes":     {expression: "*/15 * * * *", expected: time.Date(2024, time.February, 1, 10, 30, 0, 0, time.UTC)},
+		"daily at 4":           {expression: "0 4 * * *", expected: time.Date(2024, time.February, 2, 4, 0, 0, 0, time.UTC)},
+		"hour list":            {expression: "30 6,12,18 * * *", expected: time.Date(2024, time.February, 1, 12, 30, 0, 0, time.UTC)},
+		"weekdays range":       {expression: "0 9 * * 1-5", expected: time.Date(2024, time.February, 2, 9, 0, 0, 0, time.UTC)},
+		"sunday as 7":          {expression: "0 0 * * 7", expected: time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		"leap day":             {expression: "0 0 29 2 *", expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		"next year":            {expression: "0 0 1 1 *", expected: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		"day or weekday":       {expression: "0 0 15 * 6", expected: time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC)},
+		"day step and weekday": {expression: "0 0 */2 * 1", expected: time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)},
+		"day and weekday step": {expression: "0 0 10-20 * */3", expected: time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC)},
+		"range with step":      {expression: "0 0-12/6 * * *", expected: time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC)},
+		"value with step":      {expression: "5/20 * * * *", expected: time.Date(2024, time.February, 1, 10, 25, 0, 0, time.UTC)},
+		"daily alias":          {expression: "@daily", expected: time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC)},
+		"exact current minute": {expression: "17 10 1 2 *", expected: time.Date(2025, time.February, 1, 10, 17, 0, 0, time.UTC)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schedule, err := Parse(test.expression)
+			if err != nil {
+				t.Fatalf("Parse failed. Error: %s", err.Error())
+			}
+
+			actual := schedule.Next(from)
+			if !actual.Equal(test.expected) {
+				t.Errorf("Expected: %s | Actual: %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseEvery(t *testing.T) {
+	schedule, err := Parse("@every 90m")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	from := time.Date(2024, time.February, 1, 10, 17, 30, 0, time.UTC)
+	if next := schedule.Next(from); !next.Equal(from.Add(90 * time.Minute)) {
+		t.Errorf("Unexpected next activation: %s", next)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	expressions := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"0 0 31 2 *",
+		"@every abc",
+		"@every -1h",
+	}
+
+	for _, expression := range expressions {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Expected error for [%s]", expression)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Runner struct {
+	Schedule Schedule
+	Backoff  Backoff
+	// RunOnStart runs the task right away instead of waiting for the first activation.
+	RunOnStart bool
+
+	now func() time.Time
+}
+
+// Run executes the task on schedule until the context is cancelled. A failed task is retried
+// with backoff instead of waiting for the next activation. Run returns when ctx is done and
+// the running task, if any, has finished.
+func (r *Runner) Run(ctx context.Context, task func(ctx context.Context) error) error {
+	var delay time.Duration
+	if !r.RunOnStart {
+		delay = r.untilNext()
+	}
+
+	for {
+		slog.Info("Next check scheduled", "at", r.currentTime().Add(delay).Format(time.RFC3339))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.Info("Stopping scheduler", "reason", ctx.Err().Error())
+			return nil
+		case <-timer.C:
+		}
+
+		if err := task(ctx); err != nil {
+			if ctx.Err() != nil {
+				slog.Info("Stopping scheduler", "reason", ctx.Err().Error())
+				return nil
+			}
+
+			delay = r.Backoff.Next()
+			// Don't retry later than the regular schedule would run
+			if untilNext := r.untilNext(); untilNext < delay {
+				delay = untilNext
+			}
+			slog.Error("Scheduled task failed", "error", err.Error(), "failures", r.Backoff.Failures(), "retryIn", delay.Round(time.Second))
+			continue
+		}
+
+		r.Backoff.Reset()
+		delay = r.untilNext()
+	}
+}
+
+func (r *Runner) untilNext() time.Duration {
+	now := r.currentTime()
+	delay := r.Schedule.Next(now).Sub(now)
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+func (r *Runner) currentTime() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRunnerRunsTaskOnSchedule(t *testing.T) {
+	// Setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs = 0
+	runner := Runner{Schedule: Interval(time.Millisecond), RunOnStart: true}
+
+	// Run
+	err := runner.Run(ctx, func(ctx context.Context) error {
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if runs != 3 {
+		t.Errorf("Expected 3 runs. Actual: %d", runs)
+	}
+}
+
+func TestRunnerRetriesWithBackoff(t *testing.T) {
+	// Setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs = 0
+	runner := Runner{
+		Schedule:   Interval(time.Hour),
+		Backoff:    Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond},
+		RunOnStart: true,
+	}
+
+	// Run
+	err := runner.Run(ctx, func(ctx context.Context) error {
+		runs++
+		if runs < 3 {
+			return fmt.Errorf("github is down")
+		}
+		cancel()
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if runs != 3 {
+		t.Errorf("Failed task should be retried before the next scheduled run. Runs: %d", runs)
+	}
+	if runner.Backoff.Failures() != 0 {
+		t.Errorf("Backoff should be reset after success")
+	}
+}
+
+func TestRunnerStopsWhileWaiting(t *testing.T) {
+	// Setup
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	runner := Runner{Schedule: Interval(time.Hour)}
+
+	// Run
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx, func(ctx context.Context) error {
+			t.Errorf("Task should not run before the first activation")
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Runner didn't stop after cancellation")
+	}
+}
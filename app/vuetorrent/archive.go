@@ -0,0 +1,44 @@
+package vuetorrent
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ArchiveVersion reads the version from version.txt bundled in VueTorrent archives.
+func ArchiveVersion(filePath string) (string, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", fmt.Errorf("not a valid zip archive. %s", err.Error())
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		fileName, _ := strings.CutPrefix(file.Name, "vuetorrent/")
+		if fileName != "version.txt" {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+
+		// version.txt holds a single tag, anything longer is not a version file
+		content, err := io.ReadAll(io.LimitReader(reader, 128))
+		if err != nil {
+			return "", err
+		}
+
+		version, _ := strings.CutPrefix(strings.TrimSpace(string(content)), "v")
+		if version == "" {
+			return "", fmt.Errorf("version.txt in %s is empty", filePath)
+		}
+		return version, nil
+	}
+
+	return "", fmt.Errorf("version.txt not found in %s", filePath)
+}
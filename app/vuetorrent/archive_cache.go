@@ -0,0 +1,236 @@
+package vuetorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PartialDownloadsDir keeps unfinished downloads inside the cache, so they can be resumed by the next run.
+const PartialDownloadsDir = ".downloads"
+
+type CachedArchive struct {
+	Version     string
+	AssetName   string
+	DownloadUrl string
+	Digest      Digest
+	Path        string
+	Size        int64
+	// UsedAt is the time the archive was downloaded or last reused.
+	UsedAt time.Time
+}
+
+// archiveMetadata is stored next to a cached archive as <archive>.json.
+type archiveMetadata struct {
+	Version     string `json:"version"`
+	AssetName   string `json:"asset_name"`
+	DownloadUrl string `json:"download_url"`
+}
+
+// ArchiveCache keeps downloaded release archives as <Dir>/<algorithm>-<hex>.zip. An archive is reused
+// when a release with the same digest is installed again, regardless of its version or file name.
+type ArchiveCache struct {
+	Dir string
+	// Keep is the number of archives retained by Prune. Zero means no limit.
+	Keep int
+	// MaxSize is the total size in bytes of archives retained by Prune. Zero means no limit.
+	MaxSize int64
+	// Client downloads release checksum files used to look archives up. Nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// Get returns a cached archive of the release or downloads it into the cache. Cached archives are
+// verified before reuse, invalid ones are deleted and downloaded again.
+func (c ArchiveCache) Get(ctx context.Context, release Release, downloader Downloader) (string, error) {
+	release, expectedDigest, err := resolveDigest(ctx, release, c.Client)
+	if err != nil {
+		return "", err
+	}
+
+	if cached, found := c.find(ctx, release, expectedDigest); found {
+		slog.Info("Using cached archive", "version", release.Version, "file", cached.Path)
+		c.touch(cached.Path)
+		return cached.Path, nil
+	}
+
+	downloadsDir := filepath.Join(c.Dir, PartialDownloadsDir)
+	if err := os.MkdirAll(downloadsDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	filePath, err := downloader.Download(ctx, release, downloadsDir)
+	if err != nil {
+		return "", err
+	}
+
+	return c.add(filePath, release, expectedDigest.Algorithm)
+}
+
+// find looks the archive up by its published digest. Releases without a digest can only be
+// matched by download url, such archives are checked to be a valid zip of the expected size.
+func (c ArchiveCache) find(ctx context.Context, release Release, expectedDigest Digest) (CachedArchive, bool) {
+	if expectedDigest != (Digest{}) {
+		cachedPath := c.archivePath(expectedDigest)
+		if _, err := os.Stat(cachedPath); err != nil {
+			return CachedArchive{}, false
+		}
+
+		actualDigest, err := fileDigest(cachedPath, expectedDigest.Algorithm)
+		if err != nil || actualDigest != expectedDigest {
+			slog.Warn("Cached archive is corrupted. Downloading again", "file", cachedPath)
+			c.remove(cachedPath)
+			return CachedArchive{}, false
+		}
+		return CachedArchive{Version: release.Version, Digest: actualDigest, Path: cachedPath}, true
+	}
+
+	archives, err := c.List()
+	if err != nil {
+		return CachedArchive{}, false
+	}
+
+	for _, cached := range archives {
+		if release.DownloadUrl == "" || cached.DownloadUrl != release.DownloadUrl || cached.Version != release.Version {
+			continue
+		}
+		if err := validateCachedArchive(ctx, cached.Path, release, c.Client); err != nil {
+			slog.Warn("Cached archive is invalid. Downloading again", "file", cached.Path, "error", err.Error())
+			c.remove(cached.Path)
+			return CachedArchive{}, false
+		}
+		return cached, true
+	}
+
+	return CachedArchive{}, false
+}
+
+// add moves a downloaded archive into the cache under its digest.
+func (c ArchiveCache) add(filePath string, release Release, algorithm string) (string, error) {
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+
+	digest, err := fileDigest(filePath, algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	cachedPath := c.archivePath(digest)
+	if err := os.Rename(filePath, cachedPath); err != nil {
+		return "", fmt.Errorf("failed to move %s into archive cache. %s", filePath, err.Error())
+	}
+
+	metadata, err := json.Marshal(archiveMetadata{
+		Version:     release.Version,
+		AssetName:   release.AssetName,
+		DownloadUrl: release.DownloadUrl,
+	})
+	if err == nil {
+		err = os.WriteFile(metadataPath(cachedPath), metadata, 0644)
+	}
+	if err != nil {
+		slog.Warn("Can't save cached archive metadata", "file", cachedPath, "error", err.Error())
+	}
+
+	return cachedPath, nil
+}
+
+// List returns cached archives, most recently used first.
+func (c ArchiveCache) List() ([]CachedArchive, error) {
+	entries, err := os.ReadDir(c.Dir)
+	if os.IsNotExist(err) {
+		return []CachedArchive{}, nil
+	}
+	if err != nil {
+		return []CachedArchive{}, fmt.Errorf("failed to read archive cache %s. %s", c.Dir, err.Error())
+	}
+
+	var archives = []CachedArchive{}
+	for _, entry := range entries {
+		name, isArchive := strings.CutSuffix(entry.Name(), ".zip")
+		if entry.IsDir() || !isArchive {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return []CachedArchive{}, err
+		}
+
+		archivePath := filepath.Join(c.Dir, entry.Name())
+		var cached = CachedArchive{Path: archivePath, Size: info.Size(), UsedAt: info.ModTime()}
+
+		algorithm, hexDigest, _ := strings.Cut(name, "-")
+		cached.Digest = Digest{Algorithm: algorithm, Hex: hexDigest}
+
+		var metadata archiveMetadata
+		if content, err := os.ReadFile(metadataPath(archivePath)); err == nil && json.Unmarshal(content, &metadata) == nil {
+			cached.Version = metadata.Version
+			cached.AssetName = metadata.AssetName
+			cached.DownloadUrl = metadata.DownloadUrl
+		}
+
+		archives = append(archives, cached)
+	}
+
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i].UsedAt.After(archives[j].UsedAt)
+	})
+
+	return archives, nil
+}
+
+// Prune removes least recently used archives until at most Keep of them are left and their
+// total size is within MaxSize. It returns the removed archives.
+func (c ArchiveCache) Prune() ([]CachedArchive, error) {
+	archives, err := c.List()
+	if err != nil {
+		return []CachedArchive{}, err
+	}
+
+	var removed = []CachedArchive{}
+	var totalSize int64
+	for i, cached := range archives {
+		totalSize += cached.Size
+		if (c.Keep <= 0 || i < c.Keep) && (c.MaxSize <= 0 || totalSize <= c.MaxSize) {
+			continue
+		}
+
+		slog.Info("Removing cached archive", "version", cached.Version, "file", cached.Path)
+		if err := c.remove(cached.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove cached archive %s. %s", cached.Path, err.Error())
+		}
+		totalSize -= cached.Size
+		removed = append(removed, cached)
+	}
+
+	return removed, nil
+}
+
+func (c ArchiveCache) archivePath(digest Digest) string {
+	return filepath.Join(c.Dir, fmt.Sprintf("%s-%s.zip", digest.Algorithm, digest.Hex))
+}
+
+// touch marks the archive as recently used, so Prune keeps it longer.
+func (c ArchiveCache) touch(archivePath string) {
+	now := time.Now()
+	if err := os.Chtimes(archivePath, now, now); err != nil {
+		slog.Warn("Can't update cached archive time", "file", archivePath, "error", err.Error())
+	}
+}
+
+func (c ArchiveCache) remove(archivePath string) error {
+	os.Remove(metadataPath(archivePath))
+	return os.Remove(archivePath)
+}
+
+func metadataPath(archivePath string) string {
+	return archivePath + ".json"
+}
@@ -0,0 +1,231 @@
+package vuetorrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// copyingDownloader copies a local archive into outputDir and counts downloads.
+type copyingDownloader struct {
+	archivePath string
+	downloads   *int
+}
+
+func (d copyingDownloader) Download(ctx context.Context, release Release, outputDir string) (string, error) {
+	*d.downloads++
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(outputDir, "vuetorrent-"+release.Version+".zip")
+	return filePath, copyFile(d.archivePath, filePath)
+}
+
+func TestArchiveCacheReusesArchiveByDigest(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/public/index.html", Content: "<html></html>"}})
+	digest, _ := fileDigest(archivePath, "sha256")
+
+	var downloads int
+	downloader := copyingDownloader{archivePath: archivePath, downloads: &downloads}
+	cache := ArchiveCache{Dir: t.TempDir()}
+
+	// Run
+	firstPath, err := cache.Get(context.Background(), Release{Version: "2.3.0", Digest: digest.String()}, downloader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// Same archive published under another name
+	secondPath, err := cache.Get(context.Background(), Release{Version: "2.3.0-mirror", Digest: digest.String()}, downloader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if downloads != 1 {
+		t.Errorf("Expected 1 download. Actual: %d", downloads)
+	}
+	if firstPath != secondPath || filepath.Base(firstPath) != "sha256-"+digest.Hex+".zip" {
+		t.Errorf("Unexpected cached archive paths: %s, %s", firstPath, secondPath)
+	}
+}
+
+func TestArchiveCacheDownloadsChecksumFileOnce(t *testing.T) {
+	// Setup
+	var checksumRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/vuetorrent.zip":
+			w.Write([]byte("file content"))
+		case "/SHA256SUMS":
+			checksumRequests++
+			fmt.Fprintf(w, "%s  vuetorrent.zip\n", sha256Hex("file content"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	release := Release{
+		Version:     "2.3.0",
+		DownloadUrl: server.URL + "/vuetorrent.zip",
+		AssetName:   "vuetorrent.zip",
+		ChecksumUrl: server.URL + "/SHA256SUMS",
+	}
+	cache := ArchiveCache{Dir: t.TempDir()}
+
+	// Run
+	if _, err := cache.Get(context.Background(), release, HttpDownloader{}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if checksumRequests != 1 {
+		t.Errorf("Expected 1 checksum file request. Actual: %d", checksumRequests)
+	}
+}
+
+func TestArchiveCacheReplacesCorruptedArchive(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/public/index.html", Content: "<html></html>"}})
+	digest, _ := fileDigest(archivePath, "sha256")
+
+	var downloads int
+	downloader := copyingDownloader{archivePath: archivePath, downloads: &downloads}
+	cache := ArchiveCache{Dir: t.TempDir()}
+	release := Release{Version: "2.3.0", Digest: digest.String()}
+
+	cachedPath, _ := cache.Get(context.Background(), release, downloader)
+	os.WriteFile(cachedPath, []byte("truncated"), 0644)
+
+	// Run
+	filePath, err := cache.Get(context.Background(), release, downloader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if downloads != 2 {
+		t.Errorf("Corrupted archive was reused")
+	}
+	if actualDigest, _ := fileDigest(filePath, "sha256"); actualDigest != digest {
+		t.Errorf("Unexpected archive digest: %s", actualDigest)
+	}
+}
+
+func TestArchiveCacheMatchesReleasesWithoutDigestByUrl(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/public/index.html", Content: "<html></html>"}})
+
+	var downloads int
+	downloader := copyingDownloader{archivePath: archivePath, downloads: &downloads}
+	cache := ArchiveCache{Dir: t.TempDir()}
+	release := Release{Version: "2.3.0", DownloadUrl: "https://example.com/v2.3.0/vuetorrent.zip"}
+
+	// Run
+	cache.Get(context.Background(), release, downloader)
+	cache.Get(context.Background(), release, downloader)
+	cache.Get(context.Background(), Release{Version: "2.3.0", DownloadUrl: "https://example.com/other/vuetorrent.zip"}, downloader)
+
+	if downloads != 2 {
+		t.Errorf("Expected 2 downloads. Actual: %d", downloads)
+	}
+
+	// Both urls served the same content, so it's stored once
+	archives, _ := cache.List()
+	if len(archives) != 1 || archives[0].Version != "2.3.0" {
+		t.Errorf("Unexpected cached archives: %+v", archives)
+	}
+}
+
+func TestArchiveCachePrune(t *testing.T) {
+	tests := map[string]struct {
+		cache            ArchiveCache
+		expectedVersions []string
+	}{
+		"keep last": {
+			cache:            ArchiveCache{Keep: 2},
+			expectedVersions: []string{"2.3.0", "2.2.0"},
+		},
+		"max size": {
+			cache:            ArchiveCache{MaxSize: 25},
+			expectedVersions: []string{"2.3.0", "2.2.0"},
+		},
+		"keep last and max size": {
+			cache:            ArchiveCache{Keep: 2, MaxSize: 15},
+			expectedVersions: []string{"2.3.0"},
+		},
+		"no limit": {
+			cache:            ArchiveCache{},
+			expectedVersions: []string{"2.3.0", "2.2.0", "2.1.0"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			cache := test.cache
+			cache.Dir = t.TempDir()
+
+			now := time.Now()
+			for i, version := range []string{"2.3.0", "2.2.0", "2.1.0"} {
+				archivePath := filepath.Join(cache.Dir, "sha256-"+sha256Hex(version)+".zip")
+				os.WriteFile(archivePath, []byte("0123456789"), 0644)
+				os.WriteFile(metadataPath(archivePath), []byte(`{"version":"`+version+`"}`), 0644)
+				usedAt := now.Add(-time.Duration(i) * time.Hour)
+				os.Chtimes(archivePath, usedAt, usedAt)
+			}
+
+			// Run
+			if _, err := cache.Prune(); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			archives, _ := cache.List()
+			var versions []string
+			for _, archive := range archives {
+				versions = append(versions, archive.Version)
+			}
+			if len(versions) != len(test.expectedVersions) {
+				t.Fatalf("Expected versions %v. Actual: %v", test.expectedVersions, versions)
+			}
+			for i := range versions {
+				if versions[i] != test.expectedVersions[i] {
+					t.Errorf("Expected versions %v. Actual: %v", test.expectedVersions, versions)
+				}
+			}
+		})
+	}
+}
+
+func TestInstallUsesArchiveCache(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/public/index.html", Content: "<html></html>"}})
+
+	var downloads int
+	cacheDir := t.TempDir()
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: copyingDownloader{archivePath: archivePath, downloads: &downloads},
+		unzipper:   DefaultUnzipper{},
+		config:     Config{ArchiveCache: &ArchiveCache{Dir: cacheDir, Keep: 1}},
+	}
+
+	// Run
+	for _, outputDir := range []string{filepath.Join(t.TempDir(), "first"), filepath.Join(t.TempDir(), "second")} {
+		if _, err := vtManager.Install(context.Background(), "1.1.1", outputDir); err != nil {
+			t.Fatalf("Installation failed. Error: %s", err.Error())
+		}
+	}
+
+	if downloads != 1 {
+		t.Errorf("Expected 1 download. Actual: %d", downloads)
+	}
+	archives, _ := vtManager.config.ArchiveCache.List()
+	if len(archives) != 1 {
+		t.Errorf("Expected 1 cached archive. Actual: %d", len(archives))
+	}
+}
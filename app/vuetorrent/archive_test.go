@@ -0,0 +1,217 @@
+package vuetorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           []TestFile
+		expectedVersion string
+		expectError     bool
+	}{
+		{
+			name:            "version file in vuetorrent directory",
+			files:           []TestFile{{Path: "vuetorrent/version.txt", Content: "v2.3.0\n"}, {Path: "vuetorrent/public/index.html"}},
+			expectedVersion: "2.3.0",
+		},
+		{
+			name:            "version file in root",
+			files:           []TestFile{{Path: "version.txt", Content: "2.3.1"}},
+			expectedVersion: "2.3.1",
+		},
+		{
+			name:        "no version file",
+			files:       []TestFile{{Path: "vuetorrent/public/index.html"}},
+			expectError: true,
+		},
+		{
+			name:        "empty version file",
+			files:       []TestFile{{Path: "vuetorrent/version.txt", Content: " \n"}},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			archivePath := createZip(t, test.files)
+
+			// Run
+			version, err := ArchiveVersion(archivePath)
+
+			if test.expectError {
+				if err == nil {
+					t.Fatalf("Expected error, got version %s", version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Can't read archive version. Error: %s", err.Error())
+			}
+			if version != test.expectedVersion {
+				t.Fatalf("Version doesn't match. Expected %s | Actual %s", test.expectedVersion, version)
+			}
+		})
+	}
+}
+
+func TestInstallArchive(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{
+		{Path: "vuetorrent/version.txt", Content: "v2.3.0"},
+		{Path: "vuetorrent/public/index.html", Content: "<html></html>"},
+	})
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+	createInstalledVersion(t, outputDir, "2.2.0")
+
+	vtManager := vtManager{unzipper: DefaultUnzipper{}, config: Config{KeepBackups: 3}}
+
+	// Run
+	result, err := vtManager.InstallArchive(context.Background(), archivePath, "", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	expected := InstallResult{PreviousVersion: "2.2.0", Version: "2.3.0", Directory: outputDir, Changed: true}
+	if result != expected {
+		t.Fatalf("Install result doesn't match. Expected %+v | Actual %+v", expected, result)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "public", "index.html")); err != nil {
+		t.Fatalf("Archive was not extracted. Error: %s", err.Error())
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		t.Fatalf("Local archive must be kept. Error: %s", err.Error())
+	}
+	backups, _ := NewBackupStore(outputDir, "", 3).List()
+	if len(backups) != 1 || backups[0].Version != "2.2.0" {
+		t.Fatalf("Previous version was not backed up. Backups: %+v", backups)
+	}
+}
+
+func TestInstallArchiveWithExplicitVersion(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/public/index.html", Content: "<html></html>"}})
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+
+	vtManager := vtManager{unzipper: DefaultUnzipper{}}
+
+	// Run
+	if _, err := vtManager.InstallArchive(context.Background(), archivePath, "", outputDir); err == nil {
+		t.Fatal("Expected error for archive without version")
+	}
+
+	result, err := vtManager.InstallArchive(context.Background(), archivePath, "2.4.0", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	installedVersion, _ := GetInstalledVersion(outputDir)
+	if !result.Changed || installedVersion != "2.4.0" {
+		t.Fatalf("Unexpected install. Result %+v, installed version %s", result, installedVersion)
+	}
+}
+
+func TestInstallArchiveRejectsInvalidVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		archiveVersion string
+		version        string
+	}{
+		{name: "path in version file", archiveVersion: "../../victim"},
+		{name: "path in explicit version", version: "../../victim"},
+		{name: "not a version", archiveVersion: "latest"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			files := []TestFile{{Path: "vuetorrent/public/index.html", Content: "<html></html>"}}
+			if test.archiveVersion != "" {
+				files = append(files, TestFile{Path: "vuetorrent/version.txt", Content: test.archiveVersion})
+			}
+			archivePath := createZip(t, files)
+			outputDir := filepath.Join(t.TempDir(), "install", "vuetorrent")
+			victimDir := filepath.Join(filepath.Dir(outputDir), "victim")
+			if err := os.MkdirAll(victimDir, os.ModePerm); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			vtManager := vtManager{unzipper: DefaultUnzipper{}}
+
+			// Run
+			if _, err := vtManager.InstallArchive(context.Background(), archivePath, test.version, outputDir); err == nil {
+				t.Fatal("Expected error for invalid version")
+			}
+
+			if _, err := os.Stat(victimDir); err != nil {
+				t.Fatalf("Directory outside of the install location was changed. Error: %s", err.Error())
+			}
+			if _, err := os.Stat(outputDir); err == nil {
+				t.Fatal("Archive with invalid version must not be installed")
+			}
+		})
+	}
+}
+
+func TestInstallArchiveRejectsVersionMismatch(t *testing.T) {
+	tests := map[string]string{
+		"other version": "2.4.0",
+		"constraint":    "^2.0",
+	}
+
+	for name, version := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			archivePath := createZip(t, []TestFile{
+				{Path: "vuetorrent/version.txt", Content: "v2.3.0"},
+				{Path: "vuetorrent/public/index.html", Content: "<html></html>"},
+			})
+			outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+			vtManager := vtManager{unzipper: DefaultUnzipper{}}
+
+			// Run
+			if _, err := vtManager.InstallArchive(context.Background(), archivePath, version, outputDir); err == nil {
+				t.Fatalf("Expected error for version %s", version)
+			}
+
+			if _, err := os.Stat(outputDir); err == nil {
+				t.Fatal("Archive must not be installed")
+			}
+		})
+	}
+}
+
+func TestInstallFromUrl(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{
+		{Path: "vuetorrent/version.txt", Content: "v2.3.0"},
+		{Path: "vuetorrent/public/index.html", Content: "<html></html>"},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, archivePath)
+	}))
+	defer server.Close()
+
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+	vtManager := vtManager{unzipper: DefaultUnzipper{}, downloader: HttpDownloader{}}
+
+	// Run
+	result, err := vtManager.InstallFromUrl(context.Background(), server.URL+"/vuetorrent.zip", "", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	if !result.Changed || result.Version != "2.3.0" {
+		t.Fatalf("Unexpected install result %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "public", "index.html")); err != nil {
+		t.Fatalf("Archive was not extracted. Error: %s", err.Error())
+	}
+}
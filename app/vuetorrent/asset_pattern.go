@@ -0,0 +1,57 @@
+package vuetorrent
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const DefaultAssetName = "vuetorrent.zip"
+
+// AssetPattern selects the release asset to download. The pattern is a glob (e.g. "vuetorrent*.zip")
+// or a regular expression enclosed in slashes (e.g. "/^vuetorrent-.*\.zip$/").
+// The zero value matches DefaultAssetName.
+type AssetPattern struct {
+	pattern string
+	regexp  *regexp.Regexp
+}
+
+func ParseAssetPattern(pattern string) (AssetPattern, error) {
+	if pattern == "" {
+		return AssetPattern{}, nil
+	}
+
+	if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		compiled, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return AssetPattern{}, fmt.Errorf("invalid asset regexp [%s]. %s", pattern, err.Error())
+		}
+		return AssetPattern{pattern: pattern, regexp: compiled}, nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return AssetPattern{}, fmt.Errorf("invalid asset glob [%s]. %s", pattern, err.Error())
+	}
+	return AssetPattern{pattern: pattern}, nil
+}
+
+func (p AssetPattern) Match(assetName string) bool {
+	if p.regexp != nil {
+		return p.regexp.MatchString(assetName)
+	}
+
+	var pattern = p.pattern
+	if pattern == "" {
+		pattern = DefaultAssetName
+	}
+	matched, _ := path.Match(pattern, assetName)
+	return matched
+}
+
+func (p AssetPattern) String() string {
+	if p.pattern == "" {
+		return DefaultAssetName
+	}
+	return p.pattern
+}
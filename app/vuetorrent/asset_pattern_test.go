@@ -0,0 +1,85 @@
+package vuetorrent
+
+import (
+	"context"
+	"n1kit0s/vt-manager/app/github"
+	"testing"
+)
+
+func TestAssetPatternMatch(t *testing.T) {
+	tests := map[string]struct {
+		pattern    string
+		matches    []string
+		mismatches []string
+	}{
+		"default":      {pattern: "", matches: []string{"vuetorrent.zip"}, mismatches: []string{"vuetorrent.tar.gz", "source.zip"}},
+		"exact name":   {pattern: "vuetorrent.zip", matches: []string{"vuetorrent.zip"}, mismatches: []string{"vuetorrent-2.3.0.zip"}},
+		"glob":         {pattern: "vuetorrent*.zip", matches: []string{"vuetorrent.zip", "vuetorrent-2.3.0.zip"}, mismatches: []string{"vuetorrent.zip.sha256"}},
+		"regexp":       {pattern: `/^vt-\d+\.\d+\.\d+\.zip$/`, matches: []string{"vt-2.3.0.zip"}, mismatches: []string{"vt-latest.zip", "vuetorrent.zip"}},
+		"regexp parts": {pattern: "/fork/", matches: []string{"vuetorrent-fork.zip"}, mismatches: []string{"vuetorrent.zip"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pattern, err := ParseAssetPattern(test.pattern)
+			if err != nil {
+				t.Fatalf("ParseAssetPattern failed. Error: %s", err.Error())
+			}
+
+			for _, assetName := range test.matches {
+				if !pattern.Match(assetName) {
+					t.Errorf("Expected [%s] to match [%s]", assetName, test.pattern)
+				}
+			}
+			for _, assetName := range test.mismatches {
+				if pattern.Match(assetName) {
+					t.Errorf("Expected [%s] not to match [%s]", assetName, test.pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAssetPatternErrors(t *testing.T) {
+	for _, pattern := range []string{"[vuetorrent.zip", "/(vuetorrent/"} {
+		if _, err := ParseAssetPattern(pattern); err == nil {
+			t.Errorf("Expected error for pattern [%s]", pattern)
+		}
+	}
+}
+
+func TestConvertUsesAssetPattern(t *testing.T) {
+	// Setup
+	githubRelease := github.Release{
+		TagName: "v2.3.0",
+		Assets: []github.Asset{
+			{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent.zip"},
+			{Name: "vuetorrent-fork.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-fork.zip"},
+		},
+	}
+	pattern, _ := ParseAssetPattern("*-fork.zip")
+
+	// Run
+	release := convertToVuetorrentRelease(fromGithubRelease(githubRelease), pattern)
+
+	if release.DownloadUrl != "http://localhost:9876/dw/vuetorrent-fork.zip" {
+		t.Errorf("Unexpected download url: %s", release.DownloadUrl)
+	}
+}
+
+func TestInstallFailsWithoutMatchingAsset(t *testing.T) {
+	// Setup
+	pattern, _ := ParseAssetPattern("missing.zip")
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
+		config:     Config{AssetPattern: pattern},
+	}
+
+	// Run
+	_, err := vtManager.Install(context.Background(), "1.1.1", t.TempDir())
+	if err == nil {
+		t.Fatal("Expected installation to fail without matching asset")
+	}
+}
@@ -0,0 +1,300 @@
+package vuetorrent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+)
+
+type Backup struct {
+	Version   string
+	Path      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// BackupStore keeps previous VueTorrent installations as <Dir>/<version> directories.
+type BackupStore struct {
+	Dir string
+	// Keep is the number of backups retained by Prune. Zero removes all of them.
+	Keep int
+}
+
+// DefaultBackupDir returns the managed backup location for an installation directory.
+// It lives next to the installation, so backups can be swapped in with a rename.
+func DefaultBackupDir(installDir string) string {
+	cleanedDir := filepath.Clean(installDir)
+	return filepath.Join(filepath.Dir(cleanedDir), fmt.Sprintf(".%s-backups", filepath.Base(cleanedDir)))
+}
+
+func NewBackupStore(installDir string, backupDir string, keep int) BackupStore {
+	if backupDir == "" {
+		backupDir = DefaultBackupDir(installDir)
+	}
+	return BackupStore{Dir: filepath.Clean(backupDir), Keep: keep}
+}
+
+// Save moves the installation into the store. It returns an empty Backup if there is nothing to save.
+func (s BackupStore) Save(installDir string) (Backup, error) {
+	if _, err := os.Stat(installDir); os.IsNotExist(err) {
+		return Backup{}, nil
+	}
+
+	version, err := GetInstalledVersion(installDir)
+	if err != nil {
+		slog.Warn("Previous version is unknown", "error", err.Error())
+	}
+	version = backupName(version)
+
+	if err := os.MkdirAll(s.Dir, os.ModePerm); err != nil {
+		return Backup{}, fmt.Errorf("failed to create backup directory %s. %s", s.Dir, err.Error())
+	}
+
+	backupPath := filepath.Join(s.Dir, version)
+	if _, err := os.Stat(backupPath); err == nil {
+		slog.Warn("Replacing existing backup", "backup", backupPath)
+		if err := os.RemoveAll(backupPath); err != nil {
+			return Backup{}, err
+		}
+	}
+
+	slog.Info("Moving previous version into backups", "dir", installDir, "backup", backupPath)
+	if err := moveDir(installDir, backupPath); err != nil {
+		return Backup{}, err
+	}
+
+	// The backup date is kept as modification time of the backup directory
+	now := time.Now()
+	if err := os.Chtimes(backupPath, now, now); err != nil {
+		slog.Warn("Can't update backup time", "backup", backupPath, "error", err.Error())
+	}
+
+	return Backup{Version: version, Path: backupPath, CreatedAt: now}, nil
+}
+
+// backupName returns the version if it's safe to use as a directory name in the store. A missing
+// or invalid version.txt (e.g. empty or containing "..") gets a unique unknown-<timestamp> name.
+func backupName(version string) string {
+	if validateVersion(version) == nil {
+		return version
+	}
+	if version != "" && version != "unknown" {
+		slog.Warn("Installed version is not a valid version. Backing up as unknown", "version", version)
+	}
+	return "unknown-" + time.Now().Format("20060102-150405")
+}
+
+// List returns all backups, newest first.
+func (s BackupStore) List() ([]Backup, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return []Backup{}, nil
+	}
+	if err != nil {
+		return []Backup{}, fmt.Errorf("failed to read backup directory %s. %s", s.Dir, err.Error())
+	}
+
+	var backups = []Backup{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return []Backup{}, err
+		}
+
+		backupPath := filepath.Join(s.Dir, entry.Name())
+		size, err := dirSize(backupPath)
+		if err != nil {
+			return []Backup{}, fmt.Errorf("failed to calculate size of %s. %s", backupPath, err.Error())
+		}
+
+		backups = append(backups, Backup{
+			Version:   entry.Name(),
+			Path:      backupPath,
+			Size:      size,
+			CreatedAt: info.ModTime(),
+		})
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// Prune removes the oldest backups so that at most Keep of them are left.
+func (s BackupStore) Prune() error {
+	backups, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	for i := s.Keep; i < len(backups); i++ {
+		slog.Info("Removing old backup", "version", backups[i].Version, "backup", backups[i].Path)
+		if err := os.RemoveAll(backups[i].Path); err != nil {
+			return fmt.Errorf("failed to remove backup %s. %s", backups[i].Path, err.Error())
+		}
+	}
+
+	if s.Keep == 0 {
+		// Nothing is retained, so don't leave an empty managed directory behind
+		os.Remove(s.Dir)
+	}
+
+	return nil
+}
+
+// Restore swaps a retained version back into installDir. An empty version restores the newest backup.
+// The current installation is saved into the store, so a rollback can be undone.
+func (s BackupStore) Restore(version string, installDir string) (Backup, error) {
+	backup, err := s.find(version)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	// Move the wanted backup out of the way first, Save may replace a backup with the same name
+	stagingDir, err := newStagingDir(installDir)
+	if err != nil {
+		return Backup{}, err
+	}
+	// Only removed once empty, the backup is moved back if the rollback fails
+	defer os.Remove(stagingDir)
+
+	restoringPath := filepath.Join(stagingDir, filepath.Base(installDir))
+	if err := moveDir(backup.Path, restoringPath); err != nil {
+		return Backup{}, fmt.Errorf("failed to take backup %s. %s", backup.Path, err.Error())
+	}
+
+	current, err := s.Save(installDir)
+	if err != nil {
+		moveDir(restoringPath, backup.Path)
+		return Backup{}, fmt.Errorf("failed to move current version aside. %s", err.Error())
+	}
+
+	slog.Info("Restoring backup", "version", backup.Version, "dir", installDir)
+	if err := moveDir(restoringPath, installDir); err != nil {
+		moveDir(restoringPath, backup.Path)
+		restoreBackup(current.Path, installDir)
+		return Backup{}, fmt.Errorf("failed to restore backup %s. %s", backup.Path, err.Error())
+	}
+
+	if err := writeVersionFile(backup.Version, installDir); err != nil {
+		slog.Warn("Can't update version file", "error", err.Error())
+	}
+
+	if err := s.Prune(); err != nil {
+		slog.Warn("Can't prune backups", "error", err.Error())
+	}
+
+	return backup, nil
+}
+
+func (s BackupStore) find(version string) (Backup, error) {
+	backups, err := s.List()
+	if err != nil {
+		return Backup{}, err
+	}
+
+	if len(backups) == 0 {
+		return Backup{}, fmt.Errorf("no backups found in %s", s.Dir)
+	}
+
+	if version == "" {
+		return backups[0], nil
+	}
+
+	for _, backup := range backups {
+		if SameVersion(backup.Version, version) {
+			return backup, nil
+		}
+	}
+
+	return Backup{}, fmt.Errorf("backup for version %s not found in %s", version, s.Dir)
+}
+
+// moveDir renames a directory and falls back to copy and delete when the rename
+// crosses filesystems.
+func moveDir(src string, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	slog.Debug("Rename failed, copying directory instead", "src", src, "dst", dst, "error", err.Error())
+	if err := copyDir(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return fmt.Errorf("failed to copy %s into %s. %s", src, dst, err.Error())
+	}
+
+	return os.RemoveAll(src)
+}
+
+func copyDir(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(dst, relPath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return os.MkdirAll(targetPath, info.Mode().Perm())
+		}
+		if !entry.Type().IsRegular() {
+			return fmt.Errorf("unsupported file type %s", path)
+		}
+
+		srcFile, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer srcFile.Close()
+
+		dstFile, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		defer dstFile.Close()
+
+		_, err = io.Copy(dstFile, srcFile)
+		return err
+	})
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
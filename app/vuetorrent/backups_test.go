@@ -0,0 +1,217 @@
+package vuetorrent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createBackup(t *testing.T, store BackupStore, version string, createdAt time.Time) {
+	backupPath := filepath.Join(store.Dir, version)
+	createInstalledVersion(t, backupPath, version)
+	if err := os.Chtimes(backupPath, createdAt, createdAt); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestBackupStoreListNewestFirst(t *testing.T) {
+	// Setup
+	store := BackupStore{Dir: t.TempDir(), Keep: 3}
+	now := time.Now()
+	createBackup(t, store, "1.1.1", now.Add(-3*time.Hour))
+	createBackup(t, store, "1.1.3", now.Add(-1*time.Hour))
+	createBackup(t, store, "1.1.2", now.Add(-2*time.Hour))
+
+	// Run
+	backups, err := store.List()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expectedVersions := []string{"1.1.3", "1.1.2", "1.1.1"}
+	if len(backups) != len(expectedVersions) {
+		t.Fatalf("Expected %d backups. Actual: %d", len(expectedVersions), len(backups))
+	}
+	for i, backup := range backups {
+		if backup.Version != expectedVersions[i] {
+			t.Errorf("Expected version %s. Actual: %s", expectedVersions[i], backup.Version)
+		}
+		if backup.Size == 0 {
+			t.Errorf("Backup %s size is not calculated", backup.Version)
+		}
+	}
+}
+
+func TestBackupStorePrune(t *testing.T) {
+	// Setup
+	store := BackupStore{Dir: t.TempDir(), Keep: 2}
+	now := time.Now()
+	createBackup(t, store, "1.1.1", now.Add(-3*time.Hour))
+	createBackup(t, store, "1.1.2", now.Add(-2*time.Hour))
+	createBackup(t, store, "1.1.3", now.Add(-1*time.Hour))
+
+	// Run
+	if err := store.Prune(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := os.Stat(filepath.Join(store.Dir, "1.1.1")); !os.IsNotExist(err) {
+		t.Errorf("The oldest backup was not removed")
+	}
+	backups, _ := store.List()
+	if len(backups) != 2 {
+		t.Errorf("Expected 2 backups. Actual: %d", len(backups))
+	}
+}
+
+func TestBackupStoreSaveInvalidVersion(t *testing.T) {
+	tests := map[string]struct {
+		versionFile *string
+	}{
+		"missing version.txt": {},
+		"empty version.txt":   {versionFile: ptr("")},
+		"parent directory":    {versionFile: ptr("..")},
+		"path outside":        {versionFile: ptr("../x")},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			tempDir := t.TempDir()
+			store := BackupStore{Dir: filepath.Join(tempDir, "backups"), Keep: 3}
+			createBackup(t, store, "1.1.1", time.Now().Add(-time.Hour))
+			os.MkdirAll(filepath.Join(tempDir, "x"), os.ModePerm)
+
+			installDir := filepath.Join(tempDir, "vuetorrent")
+			os.MkdirAll(installDir, os.ModePerm)
+			os.WriteFile(filepath.Join(installDir, "index.html"), []byte("index"), 0644)
+			if test.versionFile != nil {
+				os.WriteFile(filepath.Join(installDir, "version.txt"), []byte(*test.versionFile), 0644)
+			}
+
+			// Run
+			backup, err := store.Save(installDir)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			if filepath.Dir(backup.Path) != store.Dir || !strings.HasPrefix(backup.Version, "unknown-") {
+				t.Errorf("Unexpected backup %s", backup.Path)
+			}
+			if _, err := os.Stat(filepath.Join(backup.Path, "index.html")); err != nil {
+				t.Errorf("Installation was not backed up")
+			}
+			if _, err := os.Stat(filepath.Join(store.Dir, "1.1.1", "version.txt")); err != nil {
+				t.Errorf("Existing backup was removed")
+			}
+			if _, err := os.Stat(filepath.Join(tempDir, "x")); err != nil {
+				t.Errorf("Directory outside of the store was removed")
+			}
+		})
+	}
+}
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestBackupStoreRestore(t *testing.T) {
+	// Setup
+	tempDir := t.TempDir()
+	installDir := filepath.Join(tempDir, "vuetorrent")
+	createInstalledVersion(t, installDir, "1.1.3")
+	store := NewBackupStore(installDir, "", 3)
+	now := time.Now()
+	createBackup(t, store, "1.1.1", now.Add(-2*time.Hour))
+	createBackup(t, store, "1.1.2", now.Add(-1*time.Hour))
+
+	// Run
+	backup, err := store.Restore("1.1.1", installDir)
+	if err != nil {
+		t.Fatalf("Restore failed. Error: %s", err.Error())
+	}
+
+	if backup.Version != "1.1.1" {
+		t.Errorf("Expected restored version 1.1.1. Actual: %s", backup.Version)
+	}
+	version, _ := GetInstalledVersion(installDir)
+	if version != "1.1.1" {
+		t.Errorf("Expected installed version 1.1.1. Actual: %s", version)
+	}
+
+	backups, _ := store.List()
+	if len(backups) != 2 || backups[0].Version != "1.1.3" || backups[1].Version != "1.1.2" {
+		t.Errorf("Current version should be kept as the newest backup. Actual: %+v", backups)
+	}
+}
+
+func TestBackupStoreRestoreLatest(t *testing.T) {
+	// Setup
+	tempDir := t.TempDir()
+	installDir := filepath.Join(tempDir, "vuetorrent")
+	createInstalledVersion(t, installDir, "1.1.3")
+	store := NewBackupStore(installDir, filepath.Join(tempDir, "backups"), 3)
+	now := time.Now()
+	createBackup(t, store, "1.1.1", now.Add(-2*time.Hour))
+	createBackup(t, store, "1.1.2", now.Add(-1*time.Hour))
+
+	// Run
+	_, err := store.Restore("", installDir)
+	if err != nil {
+		t.Fatalf("Restore failed. Error: %s", err.Error())
+	}
+
+	version, _ := GetInstalledVersion(installDir)
+	if version != "1.1.2" {
+		t.Errorf("Expected installed version 1.1.2. Actual: %s", version)
+	}
+}
+
+func TestBackupStoreRestoreUnknownVersion(t *testing.T) {
+	// Setup
+	tempDir := t.TempDir()
+	installDir := filepath.Join(tempDir, "vuetorrent")
+	createInstalledVersion(t, installDir, "1.1.3")
+	store := NewBackupStore(installDir, "", 3)
+	createBackup(t, store, "1.1.1", time.Now())
+
+	// Run
+	_, err := store.Restore("0.0.1", installDir)
+	if err == nil {
+		t.Fatal("Expected error for missing backup")
+	}
+
+	version, _ := GetInstalledVersion(installDir)
+	if version != "1.1.3" {
+		t.Errorf("Installed version should not change. Actual: %s", version)
+	}
+}
+
+func TestInstallKeepsBackups(t *testing.T) {
+	// Setup
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
+		config:     Config{KeepBackups: 1},
+	}
+	tempDir := t.TempDir()
+	outputDir := filepath.Join(tempDir, "vuetorrent")
+	createInstalledVersion(t, outputDir, "1.1.1")
+
+	// Run
+	if _, err := vtManager.Install(context.Background(), "1.1.2", outputDir); err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+	if _, err := vtManager.Install(context.Background(), "1.1.3", outputDir); err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	backups, _ := NewBackupStore(outputDir, "", 1).List()
+	if len(backups) != 1 || backups[0].Version != "1.1.2" {
+		t.Errorf("Expected only 1.1.2 backup to be kept. Actual: %+v", backups)
+	}
+}
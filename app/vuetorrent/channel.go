@@ -0,0 +1,66 @@
+package vuetorrent
+
+import "fmt"
+
+type Channel string
+
+const (
+	// ChannelStable contains only releases that are not marked as pre-release.
+	ChannelStable Channel = "stable"
+	// ChannelPrerelease contains stable releases and pre-releases.
+	ChannelPrerelease Channel = "prerelease"
+)
+
+func ParseChannel(channel string) (Channel, error) {
+	switch Channel(channel) {
+	case "", ChannelStable:
+		return ChannelStable, nil
+	case ChannelPrerelease:
+		return ChannelPrerelease, nil
+	}
+	return "", fmt.Errorf("unknown release channel [%s]", channel)
+}
+
+// Includes reports whether the release belongs to the channel. A release is treated as
+// a pre-release if it is flagged so on GitHub or its version has a pre-release suffix.
+func (c Channel) Includes(release Release) bool {
+	if c == ChannelPrerelease {
+		return true
+	}
+
+	if release.Prerelease {
+		return false
+	}
+
+	version, err := ParseSemVer(release.Version)
+	return err != nil || !version.IsPrerelease()
+}
+
+// latestRelease returns the release with the highest version. Releases with a version
+// that can't be parsed are only used if there is nothing else.
+func latestRelease(releases []Release) (Release, error) {
+	if len(releases) == 0 {
+		return Release{}, fmt.Errorf("no releases found")
+	}
+
+	var latest Release
+	var latestVersion SemVer
+	var found = false
+	for _, release := range releases {
+		version, err := ParseSemVer(release.Version)
+		if err != nil {
+			continue
+		}
+
+		if !found || latestVersion.LessThan(version) {
+			latest = release
+			latestVersion = version
+			found = true
+		}
+	}
+
+	if !found {
+		return releases[0], nil
+	}
+	return latest, nil
+}
@@ -0,0 +1,107 @@
+package vuetorrent
+
+import (
+	"context"
+	"n1kit0s/vt-manager/app/github"
+	"testing"
+)
+
+type mockChannelGithubClient struct {
+	mockGithubClient
+}
+
+func (c *mockChannelGithubClient) GetReleases(ctx context.Context) ([]github.Release, error) {
+	return []github.Release{
+		{TagName: "v2.5.0", Draft: true, Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-250.zip"}}},
+		{TagName: "v2.4.0-beta.1", Prerelease: true, Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-240b1.zip"}}},
+		{TagName: "v2.2.1", Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-221.zip"}}},
+		{TagName: "v2.3.0", Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-230.zip"}}},
+		{TagName: "v2.3.1-rc.1", Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-231rc1.zip"}}},
+	}, nil
+}
+
+func TestGetLatestReleaseByChannel(t *testing.T) {
+	tests := map[string]struct {
+		channel         Channel
+		expectedVersion string
+	}{
+		"default channel":    {channel: "", expectedVersion: "2.3.0"},
+		"stable channel":     {channel: ChannelStable, expectedVersion: "2.3.0"},
+		"prerelease channel": {channel: ChannelPrerelease, expectedVersion: "2.4.0-beta.1"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			vtManager := NewVTManager(GithubProvider{Client: &mockChannelGithubClient{}}, Config{Channel: test.channel})
+
+			// Run
+			release, err := vtManager.GetLatestRelease(context.Background())
+			if err != nil {
+				t.Fatalf("GetLatestRelease failed. Error: %s", err.Error())
+			}
+
+			if release.Version != test.expectedVersion {
+				t.Errorf("Expected version %s. Actual: %s", test.expectedVersion, release.Version)
+			}
+		})
+	}
+}
+
+func TestGetAllReleasesSkipsDrafts(t *testing.T) {
+	// Setup
+	vtManager := NewVTManager(GithubProvider{Client: &mockChannelGithubClient{}}, Config{Channel: ChannelPrerelease})
+
+	// Run
+	releases, err := vtManager.GetAllReleases(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, release := range releases {
+		if release.Version == "2.5.0" {
+			t.Errorf("Draft release should not be listed")
+		}
+	}
+	if len(releases) != 4 {
+		t.Errorf("Expected 4 releases. Actual: %d", len(releases))
+	}
+}
+
+func TestGetLatestReleaseWithoutReleases(t *testing.T) {
+	// Setup
+	vtManager := NewVTManager(GithubProvider{Client: &emptyGithubClient{}}, Config{})
+
+	// Run
+	_, err := vtManager.GetLatestRelease(context.Background())
+	if err == nil {
+		t.Fatal("Expected error when there are no releases")
+	}
+}
+
+type emptyGithubClient struct {
+	mockGithubClient
+}
+
+func (c *emptyGithubClient) GetReleases(ctx context.Context) ([]github.Release, error) {
+	return []github.Release{}, nil
+}
+
+func TestParseChannel(t *testing.T) {
+	tests := map[string]Channel{
+		"":           ChannelStable,
+		"stable":     ChannelStable,
+		"prerelease": ChannelPrerelease,
+	}
+
+	for value, expected := range tests {
+		channel, err := ParseChannel(value)
+		if err != nil || channel != expected {
+			t.Errorf("ParseChannel(%q). Expected: %s | Actual: %s, %v", value, expected, channel, err)
+		}
+	}
+
+	if _, err := ParseChannel("nightly"); err == nil {
+		t.Errorf("Expected error for unknown channel")
+	}
+}
@@ -0,0 +1,213 @@
+package vuetorrent
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const ChecksumFileName = "version.sha256"
+
+// checksumAssetNames are release assets that list checksums of the other assets.
+var checksumAssetNames = []string{"sha256sums", "sha256sums.txt", "checksums.txt", "checksums.sha256"}
+
+// isChecksumAsset reports whether the asset contains checksums for the archive asset.
+func isChecksumAsset(assetName string, archiveName string) bool {
+	if strings.EqualFold(assetName, archiveName+".sha256") {
+		return true
+	}
+	for _, name := range checksumAssetNames {
+		if strings.EqualFold(assetName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Digest is a hash of a file in <algorithm>:<hex> form.
+type Digest struct {
+	Algorithm string
+	Hex       string
+}
+
+func ParseDigest(digest string) (Digest, error) {
+	algorithm, value, found := strings.Cut(digest, ":")
+	if !found {
+		return Digest{}, fmt.Errorf("invalid digest [%s]. Expected <algorithm>:<hex>", digest)
+	}
+
+	algorithm = strings.ToLower(algorithm)
+	if _, err := newHash(algorithm); err != nil {
+		return Digest{}, err
+	}
+	if _, err := hex.DecodeString(value); err != nil {
+		return Digest{}, fmt.Errorf("invalid digest [%s]. %s", digest, err.Error())
+	}
+
+	return Digest{Algorithm: algorithm, Hex: strings.ToLower(value)}, nil
+}
+
+func (d Digest) String() string {
+	return d.Algorithm + ":" + d.Hex
+}
+
+func newHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported digest algorithm [%s]", algorithm)
+}
+
+func fileDigest(filePath string, algorithm string) (Digest, error) {
+	hasher, err := newHash(algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return Digest{}, err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(hasher, file); err != nil {
+		return Digest{}, err
+	}
+
+	return Digest{Algorithm: algorithm, Hex: hex.EncodeToString(hasher.Sum(nil))}, nil
+}
+
+// verifyArchive checks the downloaded archive against the asset size and digest. If the asset has
+// no digest, the release checksum file is used. It returns the verified digest, or a sha256 digest
+// of the file when there was nothing to verify against.
+func verifyArchive(ctx context.Context, filePath string, release Release, client *http.Client) (Digest, error) {
+	if release.Size > 0 {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return Digest{}, err
+		}
+		if info.Size() != release.Size {
+			return Digest{}, fmt.Errorf("size mismatch for %s. expected %d bytes, got %d", filePath, release.Size, info.Size())
+		}
+	}
+
+	expectedDigest, err := expectedArchiveDigest(ctx, release, client)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	if expectedDigest == (Digest{}) {
+		slog.Warn("No checksum published for release. Skipping digest verification", "version", release.Version)
+		return fileDigest(filePath, "sha256")
+	}
+
+	actualDigest, err := fileDigest(filePath, expectedDigest.Algorithm)
+	if err != nil {
+		return Digest{}, err
+	}
+	if actualDigest != expectedDigest {
+		return Digest{}, fmt.Errorf("checksum mismatch for %s. expected %s, got %s", filePath, expectedDigest, actualDigest)
+	}
+
+	slog.Info("Archive checksum verified", "digest", actualDigest.String())
+	return actualDigest, nil
+}
+
+// resolveDigest sets the release digest from its checksum file, so the file is downloaded only once
+// when the archive is looked up and verified.
+func resolveDigest(ctx context.Context, release Release, client *http.Client) (Release, Digest, error) {
+	expectedDigest, err := expectedArchiveDigest(ctx, release, client)
+	if err != nil {
+		return release, Digest{}, err
+	}
+	if expectedDigest != (Digest{}) {
+		release.Digest = expectedDigest.String()
+	}
+	return release, expectedDigest, nil
+}
+
+func expectedArchiveDigest(ctx context.Context, release Release, client *http.Client) (Digest, error) {
+	if release.Digest != "" {
+		return ParseDigest(release.Digest)
+	}
+
+	if release.ChecksumUrl == "" {
+		return Digest{}, nil
+	}
+
+	checksums, err := downloadChecksums(ctx, release.ChecksumUrl, client)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	if checksum, ok := checksums[release.AssetName]; ok {
+		return ParseDigest("sha256:" + checksum)
+	}
+	// A <asset>.sha256 file may contain a single hash without file name
+	if checksum, ok := checksums[""]; ok {
+		return ParseDigest("sha256:" + checksum)
+	}
+
+	return Digest{}, fmt.Errorf("checksum for %s not found in %s", release.AssetName, release.ChecksumUrl)
+}
+
+func downloadChecksums(ctx context.Context, checksumUrl string, client *http.Client) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", checksumUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient(client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checksums. %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if err := httpclient.CheckStatus(resp, nil); err != nil {
+		return nil, fmt.Errorf("failed to download checksums from %s. %w", checksumUrl, err)
+	}
+
+	return parseChecksums(resp.Body)
+}
+
+// parseChecksums reads sha256sum output ("<hex>  <name>" or "<hex> *<name>") and returns hashes by file name.
+func parseChecksums(reader io.Reader) (map[string]string, error) {
+	var checksums = map[string]string{}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var name = ""
+		if len(fields) > 1 {
+			name = filepath.Base(strings.TrimPrefix(fields[1], "*"))
+		}
+		checksums[name] = strings.ToLower(fields[0])
+	}
+
+	return checksums, scanner.Err()
+}
+
+// writeChecksumFile writes the digest in sha256sum format, so the asset can be checked with "sha256sum -c".
+func writeChecksumFile(digest Digest, assetName string, outputDir string) error {
+	filePath := filepath.Join(filepath.Clean(outputDir), ChecksumFileName)
+	content := fmt.Sprintf("%s  %s\n", digest.Hex, assetName)
+	return os.WriteFile(filePath, []byte(content), 0644)
+}
@@ -0,0 +1,88 @@
+package vuetorrent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	// Setup
+	content := "aaa111  vuetorrent.zip\nBBB222 *dist/vuetorrent-src.tar.gz\n\n"
+
+	// Run
+	checksums, err := parseChecksums(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if checksums["vuetorrent.zip"] != "aaa111" {
+		t.Errorf("Unexpected checksum for vuetorrent.zip: %s", checksums["vuetorrent.zip"])
+	}
+	if checksums["vuetorrent-src.tar.gz"] != "bbb222" {
+		t.Errorf("Unexpected checksum for vuetorrent-src.tar.gz: %s", checksums["vuetorrent-src.tar.gz"])
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	digest, err := ParseDigest("SHA256:ABCDEF")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if digest.String() != "sha256:abcdef" {
+		t.Errorf("Unexpected digest: %s", digest.String())
+	}
+
+	for _, value := range []string{"abcdef", "md5:abcdef", "sha256:xyz"} {
+		if _, err := ParseDigest(value); err == nil {
+			t.Errorf("Expected error for digest [%s]", value)
+		}
+	}
+}
+
+func TestIsChecksumAsset(t *testing.T) {
+	for _, name := range []string{"SHA256SUMS", "sha256sums.txt", "checksums.txt", "vuetorrent.zip.sha256"} {
+		if !isChecksumAsset(name, "vuetorrent.zip") {
+			t.Errorf("Expected [%s] to be a checksum asset", name)
+		}
+	}
+	if isChecksumAsset("vuetorrent.zip", "vuetorrent.zip") {
+		t.Errorf("Archive should not be a checksum asset")
+	}
+}
+
+func TestInstallRecordsChecksum(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/public/index.html", Content: "<html></html>"}})
+	expectedDigest, _ := fileDigest(archivePath, "sha256")
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: fixedPathDownloader{filePath: archivePath},
+		unzipper:   DefaultUnzipper{},
+	}
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+
+	// Run
+	if _, err := vtManager.Install(context.Background(), "1.1.1", outputDir); err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, ChecksumFileName))
+	if err != nil {
+		t.Fatalf("Checksum file was not created. Error: %s", err.Error())
+	}
+	expectedContent := expectedDigest.Hex + "  vuetorrent.zip\n"
+	if string(content) != expectedContent {
+		t.Errorf("Unexpected checksum file content. Expected %q | Actual %q", expectedContent, string(content))
+	}
+}
+
+type fixedPathDownloader struct {
+	filePath string
+}
+
+func (m fixedPathDownloader) Download(ctx context.Context, release Release, outputDir string) (filePath string, err error) {
+	return m.filePath, nil
+}
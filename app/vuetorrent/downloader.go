@@ -1,45 +1,210 @@
 package vuetorrent
 
 import (
+	"archive/zip"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"n1kit0s/vt-manager/app/httpclient"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Downloader interface {
-	Download(release Release, outputDir string) (filePath string, err error)
+	Download(ctx context.Context, release Release, outputDir string) (filePath string, err error)
 }
 
-type HttpDownloader struct{}
+type HttpDownloader struct {
+	// Client executes downloads, including release checksum files. Nil means http.DefaultClient.
+	Client *http.Client
+	// OnProgress receives progress events of HTTP downloads. It may be nil.
+	OnProgress ProgressFunc
+	// LimitRate is the maximum download speed in bytes per second. Zero means no limit.
+	LimitRate int64
+}
 
-func (d HttpDownloader) Download(release Release, outputDir string) (filePath string, err error) {
+// Download saves the release archive into outputDir and verifies it against the published
+// size and checksum. An archive that fails verification is deleted.
+//
+// Data is written into <file>.part and renamed once complete. If a previous download was
+// interrupted, it's resumed with a Range request. A download cancelled through ctx is deleted instead.
+// file:// urls (e.g. from a release bundle) are copied.
+func (d HttpDownloader) Download(ctx context.Context, release Release, outputDir string) (filePath string, err error) {
 	var filename = fmt.Sprintf("vuetorrent-%s.zip", release.Version)
 	filePath = filepath.Join(outputDir, filename)
 
+	release, _, err = resolveDigest(ctx, release, d.Client)
+	if err != nil {
+		return "", err
+	}
+
 	if _, err := os.Stat(filePath); err == nil {
-		slog.Info(fmt.Sprintf("%s already exists here %s. skipping download", filename, filePath))
-		return filePath, nil
+		if err := validateCachedArchive(ctx, filePath, release, d.Client); err == nil {
+			slog.Info(fmt.Sprintf("%s already exists here %s. skipping download", filename, filePath))
+			return filePath, nil
+		} else {
+			slog.Warn("Existing archive is invalid. Downloading again", "file", filePath, "error", err.Error())
+			os.Remove(filePath)
+		}
 	}
 
-	file, err := os.Create(filePath)
-	if err != nil {
+	partPath := filePath + ".part"
+	if err := d.save(ctx, release.DownloadUrl, partPath); err != nil {
+		if ctx.Err() != nil {
+			os.Remove(partPath)
+			os.Remove(validatorPath(partPath))
+			return "", ctx.Err()
+		}
+		// The partial file is kept, so the next run can resume it
 		return "", err
 	}
-	defer file.Close()
 
-	resp, err := http.Get(release.DownloadUrl)
-	if err != nil {
+	if err := os.Rename(partPath, filePath); err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
+	os.Remove(validatorPath(partPath))
 
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
+	if _, err := verifyArchive(ctx, filePath, release, d.Client); err != nil {
+		os.Remove(filePath)
 		return "", err
 	}
 
 	return filePath, nil
 }
+
+func (d HttpDownloader) save(ctx context.Context, downloadUrl string, partPath string) error {
+	if parsedUrl, err := url.Parse(downloadUrl); err == nil && parsedUrl.Scheme == "file" {
+		return copyFile(parsedUrl.Path, partPath)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	var offset int64 = 0
+	if info, err := os.Stat(partPath); err == nil && info.Size() > 0 {
+		// Without a validator the server can't tell us if the file changed, so start over
+		if validator, err := os.ReadFile(validatorPath(partPath)); err == nil && len(validator) > 0 {
+			offset = info.Size()
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			req.Header.Set("If-Range", string(validator))
+			slog.Info("Resuming download", "file", partPath, "offset", offset)
+		}
+	}
+
+	resp, err := httpClient(d.Client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return fmt.Errorf("unexpected content range [%s] for %s", resp.Header.Get("Content-Range"), downloadUrl)
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file can't be resumed. Drop it and let the next attempt start over
+		os.Remove(partPath)
+		os.Remove(validatorPath(partPath))
+		return fmt.Errorf("failed to resume download of %s. http code %d", downloadUrl, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		// The body is an error page, it must not be saved as the archive
+		if err := httpclient.CheckStatus(resp, nil); err != nil {
+			return fmt.Errorf("failed to download %s. %w", downloadUrl, err)
+		}
+		return fmt.Errorf("failed to download %s. unexpected http code %d", downloadUrl, resp.StatusCode)
+	default:
+		offset = 0
+	}
+
+	if err := saveValidator(resp, partPath); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var total int64 = -1
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	body := newProgressReader(resp.Body, downloadUrl, offset, total, d.OnProgress, d.LimitRate)
+	defer body.finish()
+
+	written, err := io.Copy(file, body)
+	if err != nil {
+		return fmt.Errorf("download of %s interrupted after %d bytes. %s", downloadUrl, offset+written, err.Error())
+	}
+
+	if resp.ContentLength >= 0 && written != resp.ContentLength {
+		return fmt.Errorf("download of %s is incomplete. expected %d bytes, got %d", downloadUrl, resp.ContentLength, written)
+	}
+
+	return nil
+}
+
+func copyFile(src string, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	_, err = io.Copy(dstFile, srcFile)
+	return err
+}
+
+// validatorPath returns the file keeping ETag or Last-Modified of a partial download.
+func validatorPath(partPath string) string {
+	return partPath + ".validator"
+}
+
+func saveValidator(resp *http.Response, partPath string) error {
+	var validator = resp.Header.Get("ETag")
+	// Weak ETags can't be used with If-Range
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = resp.Header.Get("Last-Modified")
+	}
+
+	if validator == "" {
+		os.Remove(validatorPath(partPath))
+		return nil
+	}
+	return os.WriteFile(validatorPath(partPath), []byte(validator), 0644)
+}
+
+// validateCachedArchive checks an archive left by a previous run before it's reused.
+func validateCachedArchive(ctx context.Context, filePath string, release Release, client *http.Client) error {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return fmt.Errorf("not a valid zip archive. %s", err.Error())
+	}
+	archive.Close()
+
+	_, err = verifyArchive(ctx, filePath, release, client)
+	return err
+}
+
+func httpClient(client *http.Client) *http.Client {
+	if client == nil {
+		return http.DefaultClient
+	}
+	return client
+}
@@ -1,11 +1,20 @@
 package vuetorrent
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"n1kit0s/vt-manager/app/httpclient"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDownload(t *testing.T) {
@@ -24,7 +33,7 @@ func TestDownload(t *testing.T) {
 	downloader := HttpDownloader{}
 
 	// Run
-	downloadedFilePath, err := downloader.Download(vtRelease, outputDir)
+	downloadedFilePath, err := downloader.Download(context.Background(), vtRelease, outputDir)
 	if err != nil {
 		t.Fatalf("download failed. error: %s", err.Error())
 	}
@@ -48,13 +57,352 @@ func TestDownloadShouldSkipIfFileExists(t *testing.T) {
 	}
 	outputDir := t.TempDir()
 	expectedFilename := "vuetorrent-1.2.zip"
-	os.Create(filepath.Join(outputDir, expectedFilename))
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/version.txt", Content: "1.2"}})
+	os.Rename(archivePath, filepath.Join(outputDir, expectedFilename))
 
 	downloader := HttpDownloader{}
 
 	// Run
-	_, err := downloader.Download(vtRelease, outputDir)
+	_, err := downloader.Download(context.Background(), vtRelease, outputDir)
 	if err != nil {
 		t.Fatalf("download failed. error: %s", err.Error())
 	}
 }
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadVerifiesDigest(t *testing.T) {
+	tests := map[string]struct {
+		release       Release
+		expectedError bool
+	}{
+		"matching size and digest": {
+			release:       Release{Version: "1.2", Size: 12, Digest: "sha256:" + sha256Hex("file content")},
+			expectedError: false,
+		},
+		"size mismatch": {
+			release:       Release{Version: "1.2", Size: 100},
+			expectedError: true,
+		},
+		"digest mismatch": {
+			release:       Release{Version: "1.2", Digest: "sha256:" + sha256Hex("other content")},
+			expectedError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("file content"))
+			}))
+			defer server.Close()
+
+			test.release.DownloadUrl = server.URL
+			outputDir := t.TempDir()
+
+			// Run
+			_, err := HttpDownloader{}.Download(context.Background(), test.release, outputDir)
+			if test.expectedError != (err != nil) {
+				t.Fatalf("Expected error: %t. Actual: %v", test.expectedError, err)
+			}
+
+			_, statErr := os.Stat(filepath.Join(outputDir, "vuetorrent-1.2.zip"))
+			if test.expectedError && statErr == nil {
+				t.Errorf("Invalid archive was not deleted")
+			}
+		})
+	}
+}
+
+func TestDownloadVerifiesChecksumFile(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/vuetorrent.zip":
+			w.Write([]byte("file content"))
+		case "/SHA256SUMS":
+			fmt.Fprintf(w, "%s  vuetorrent-src.tar.gz\n%s *vuetorrent.zip\n", sha256Hex("source"), sha256Hex("file content"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	vtRelease := Release{
+		Version:     "1.2",
+		DownloadUrl: server.URL + "/vuetorrent.zip",
+		AssetName:   "vuetorrent.zip",
+		ChecksumUrl: server.URL + "/SHA256SUMS",
+	}
+
+	// Run
+	_, err := HttpDownloader{}.Download(context.Background(), vtRelease, t.TempDir())
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+}
+
+func TestDownloadFailsOnErrorStatus(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<html>Not Found</html>"))
+	}))
+	defer server.Close()
+
+	vtRelease := Release{Version: "1.2", DownloadUrl: server.URL}
+	outputDir := t.TempDir()
+
+	// Run
+	_, err := HttpDownloader{}.Download(context.Background(), vtRelease, outputDir)
+	if !errors.Is(err, httpclient.ErrNotFound) {
+		t.Fatalf("Expected not found error. Actual: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "vuetorrent-1.2.zip")); err == nil {
+		t.Errorf("Error page was saved as archive")
+	}
+}
+
+func TestDownloadReplacesInvalidExistingFile(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("file content"))
+	}))
+	defer server.Close()
+
+	vtRelease := Release{Version: "1.2", DownloadUrl: server.URL, Digest: "sha256:" + sha256Hex("file content")}
+	outputDir := t.TempDir()
+	os.WriteFile(filepath.Join(outputDir, "vuetorrent-1.2.zip"), []byte("truncated"), 0644)
+
+	// Run
+	filePath, err := HttpDownloader{}.Download(context.Background(), vtRelease, outputDir)
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "file content" {
+		t.Errorf("Invalid file was reused. Content: %s", string(content))
+	}
+}
+
+func serveContent(content string, etag string, requests *[]*http.Request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r)
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "vuetorrent.zip", time.Time{}, strings.NewReader(content))
+	}))
+}
+
+func TestDownloadResumesPartialFile(t *testing.T) {
+	// Setup
+	var requests []*http.Request
+	server := serveContent("0123456789", `"v1"`, &requests)
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	partPath := filepath.Join(outputDir, "vuetorrent-1.2.zip.part")
+	os.WriteFile(partPath, []byte("01234"), 0644)
+	os.WriteFile(validatorPath(partPath), []byte(`"v1"`), 0644)
+
+	vtRelease := Release{Version: "1.2", DownloadUrl: server.URL, Size: 10}
+
+	// Run
+	filePath, err := HttpDownloader{}.Download(context.Background(), vtRelease, outputDir)
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+
+	if requests[0].Header.Get("Range") != "bytes=5-" {
+		t.Errorf("Download was not resumed. Range: %s", requests[0].Header.Get("Range"))
+	}
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "0123456789" {
+		t.Errorf("Unexpected content: %s", string(content))
+	}
+	if _, err := os.Stat(partPath); !os.IsNotExist(err) {
+		t.Errorf("Partial file was not renamed")
+	}
+	if _, err := os.Stat(validatorPath(partPath)); !os.IsNotExist(err) {
+		t.Errorf("Validator file was not removed")
+	}
+}
+
+func TestDownloadRestartsWhenFileChanged(t *testing.T) {
+	// Setup
+	var requests []*http.Request
+	server := serveContent("abcdefghij", `"v2"`, &requests)
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	partPath := filepath.Join(outputDir, "vuetorrent-1.2.zip.part")
+	os.WriteFile(partPath, []byte("01234"), 0644)
+	os.WriteFile(validatorPath(partPath), []byte(`"v1"`), 0644)
+
+	vtRelease := Release{Version: "1.2", DownloadUrl: server.URL}
+
+	// Run
+	filePath, err := HttpDownloader{}.Download(context.Background(), vtRelease, outputDir)
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "abcdefghij" {
+		t.Errorf("Stale partial data was kept. Content: %s", string(content))
+	}
+}
+
+func TestDownloadKeepsPartialFileOnInterruption(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("01234"))
+	}))
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	vtRelease := Release{Version: "1.2", DownloadUrl: server.URL}
+
+	// Run
+	_, err := HttpDownloader{}.Download(context.Background(), vtRelease, outputDir)
+	if err == nil {
+		t.Fatal("Expected interrupted download to fail")
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "vuetorrent-1.2.zip")); err == nil {
+		t.Errorf("Incomplete download was renamed to the final name")
+	}
+	partContent, _ := os.ReadFile(filepath.Join(outputDir, "vuetorrent-1.2.zip.part"))
+	if string(partContent) != "01234" {
+		t.Errorf("Partial file was not kept. Content: %s", string(partContent))
+	}
+}
+
+func TestInstallWithoutCacheResumesDownload(t *testing.T) {
+	// Setup
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("ETag", `"v1"`)
+		if len(requests) == 1 {
+			w.Header().Set("Content-Length", "10")
+			w.Write([]byte("01234"))
+			return
+		}
+		http.ServeContent(w, r, "vuetorrent.zip", time.Time{}, strings.NewReader("0123456789"))
+	}))
+	defer server.Close()
+
+	downloadDir := t.TempDir()
+	vtManager := vtManager{downloader: HttpDownloader{}, config: Config{DownloadDir: downloadDir}}
+	vtRelease := Release{Version: "1.2", DownloadUrl: server.URL, Size: 10}
+
+	// Run
+	if _, _, err := vtManager.download(context.Background(), vtRelease); err == nil {
+		t.Fatal("Expected interrupted download to fail")
+	}
+	filePath, cleanup, err := vtManager.download(context.Background(), vtRelease)
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+
+	if requests[1].Header.Get("Range") != "bytes=5-" {
+		t.Errorf("Download was not resumed. Range: %s", requests[1].Header.Get("Range"))
+	}
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "0123456789" {
+		t.Errorf("Unexpected content: %s", string(content))
+	}
+
+	cleanup()
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Errorf("Downloaded archive was not removed after install")
+	}
+}
+
+func TestDownloadRemovesPartialFileOnCancel(t *testing.T) {
+	// Setup
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("01234"))
+		w.(http.Flusher).Flush()
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	vtRelease := Release{Version: "1.2", DownloadUrl: server.URL}
+
+	// Run
+	_, err := HttpDownloader{}.Download(ctx, vtRelease, outputDir)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected cancelled download. Actual: %v", err)
+	}
+
+	entries, _ := os.ReadDir(outputDir)
+	if len(entries) != 0 {
+		t.Errorf("Download files were not removed. Files: %v", entries)
+	}
+}
+
+func TestDownloadCopiesLocalFile(t *testing.T) {
+	// Setup
+	sourcePath := filepath.Join(t.TempDir(), "vuetorrent.zip")
+	os.WriteFile(sourcePath, []byte("file content"), 0644)
+
+	fileUrl := (&url.URL{Scheme: "file", Path: filepath.ToSlash(sourcePath)}).String()
+	vtRelease := Release{Version: "1.2", DownloadUrl: fileUrl, Digest: "sha256:" + sha256Hex("file content")}
+
+	// Run
+	filePath, err := HttpDownloader{}.Download(context.Background(), vtRelease, t.TempDir())
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "file content" {
+		t.Errorf("Unexpected file content: %s", string(content))
+	}
+	if _, err := os.Stat(sourcePath); err != nil {
+		t.Errorf("Source file must be kept. Error: %s", err.Error())
+	}
+}
+
+func TestDownloadRetriesServerErrors(t *testing.T) {
+	// Setup
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>Bad Gateway</html>"))
+			return
+		}
+		w.Write([]byte("file content"))
+	}))
+	defer server.Close()
+
+	client, _ := httpclient.NewClient(httpclient.Config{Retries: 2, RetryDelay: time.Millisecond})
+	downloader := HttpDownloader{Client: client}
+
+	// Run
+	filePath, err := downloader.Download(context.Background(), Release{Version: "1.2", DownloadUrl: server.URL}, t.TempDir())
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+
+	content, _ := os.ReadFile(filePath)
+	if string(content) != "file content" {
+		t.Errorf("Error page was saved as archive. Content: %s", string(content))
+	}
+}
@@ -0,0 +1,121 @@
+package vuetorrent
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	minisignUntrustedComment = "untrusted comment:"
+	minisignTrustedComment   = "trusted comment: "
+	// minisignAlgorithm signs the file itself, minisignHashedAlgorithm its BLAKE2b-512 hash (default since minisign 0.10).
+	minisignAlgorithm       = "Ed"
+	minisignHashedAlgorithm = "ED"
+)
+
+// MinisignPublicKey is a decoded minisign public key (https://jedisct1.github.io/minisign/).
+type MinisignPublicKey struct {
+	KeyId [8]byte
+	Key   ed25519.PublicKey
+}
+
+// ParseMinisignPublicKey accepts the content of a minisign.pub file or only its base64 line.
+func ParseMinisignPublicKey(text string) (MinisignPublicKey, error) {
+	var encoded string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, minisignUntrustedComment) {
+			encoded = line
+			break
+		}
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(decoded) != 2+8+ed25519.PublicKeySize || string(decoded[:2]) != minisignAlgorithm {
+		return MinisignPublicKey{}, fmt.Errorf("invalid minisign public key")
+	}
+
+	var publicKey = MinisignPublicKey{Key: ed25519.PublicKey(decoded[10:])}
+	copy(publicKey.KeyId[:], decoded[2:10])
+	return publicKey, nil
+}
+
+type MinisignVerifier struct {
+	PublicKey MinisignPublicKey
+}
+
+func (v MinisignVerifier) Extensions() []string {
+	return []string{".minisig"}
+}
+
+// Verify checks the file signature and the signature of its trusted comment.
+func (v MinisignVerifier) Verify(filePath string, signature []byte) error {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(string(signature)), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], minisignUntrustedComment) || !strings.HasPrefix(lines[2], minisignTrustedComment) {
+		return fmt.Errorf("invalid minisign signature format")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(decoded) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("invalid minisign signature")
+	}
+	algorithm, keyId, fileSignature := string(decoded[:2]), decoded[2:10], decoded[10:]
+
+	if !bytes.Equal(keyId, v.PublicKey.KeyId[:]) {
+		return fmt.Errorf("signed with key %X, expected key %X", reverse(keyId), reverse(v.PublicKey.KeyId[:]))
+	}
+
+	message, err := minisignMessage(filePath, algorithm)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(v.PublicKey.Key, message, fileSignature) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	trustedComment := strings.TrimPrefix(lines[2], minisignTrustedComment)
+	globalSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	signedComment := append(append([]byte{}, fileSignature...), trustedComment...)
+	if err != nil || !ed25519.Verify(v.PublicKey.Key, signedComment, globalSignature) {
+		return fmt.Errorf("invalid signature of trusted comment")
+	}
+
+	return nil
+}
+
+func minisignMessage(filePath string, algorithm string) ([]byte, error) {
+	switch algorithm {
+	case minisignAlgorithm:
+		return os.ReadFile(filePath)
+	case minisignHashedAlgorithm:
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		hasher, _ := blake2b.New512(nil)
+		if _, err := io.Copy(hasher, file); err != nil {
+			return nil, err
+		}
+		return hasher.Sum(nil), nil
+	}
+	return nil, fmt.Errorf("unsupported minisign algorithm [%s]", hex.EncodeToString([]byte(algorithm)))
+}
+
+// reverse returns the key id in the order minisign prints it.
+func reverse(keyId []byte) []byte {
+	var reversed = make([]byte, len(keyId))
+	for i, b := range keyId {
+		reversed[len(keyId)-1-i] = b
+	}
+	return reversed
+}
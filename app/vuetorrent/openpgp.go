@@ -0,0 +1,62 @@
+package vuetorrent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// OpenPGPVerifier checks detached OpenPGP signatures, armored (.asc) or binary (.sig).
+type OpenPGPVerifier struct {
+	KeyRing openpgp.EntityList
+}
+
+// NewOpenPGPVerifier reads an armored or binary public key ring.
+func NewOpenPGPVerifier(keyRing io.Reader) (OpenPGPVerifier, error) {
+	reader := bufio.NewReader(keyRing)
+
+	var entities openpgp.EntityList
+	var err error
+	if isArmored(reader) {
+		entities, err = openpgp.ReadArmoredKeyRing(reader)
+	} else {
+		entities, err = openpgp.ReadKeyRing(reader)
+	}
+	if err != nil {
+		return OpenPGPVerifier{}, fmt.Errorf("invalid OpenPGP public key. %s", err.Error())
+	}
+	if len(entities) == 0 {
+		return OpenPGPVerifier{}, fmt.Errorf("OpenPGP key ring has no keys")
+	}
+
+	return OpenPGPVerifier{KeyRing: entities}, nil
+}
+
+func (v OpenPGPVerifier) Extensions() []string {
+	return []string{".asc", ".sig"}
+}
+
+func (v OpenPGPVerifier) Verify(filePath string, signature []byte) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	signatureReader := bufio.NewReader(bytes.NewReader(signature))
+	if isArmored(signatureReader) {
+		_, err = openpgp.CheckArmoredDetachedSignature(v.KeyRing, file, signatureReader, nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(v.KeyRing, file, signatureReader, nil)
+	}
+	return err
+}
+
+func isArmored(reader *bufio.Reader) bool {
+	start, _ := reader.Peek(64)
+	return bytes.HasPrefix(bytes.TrimSpace(start), []byte("-----BEGIN PGP"))
+}
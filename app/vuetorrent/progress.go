@@ -0,0 +1,110 @@
+package vuetorrent
+
+import (
+	"io"
+	"time"
+)
+
+// Progress of a download. Total is -1 if the server didn't send the size.
+type Progress struct {
+	Url        string
+	Downloaded int64
+	Total      int64
+	// Rate is the average speed of the current download in bytes per second.
+	Rate float64
+	// ETA is zero if Total or Rate is unknown.
+	ETA time.Duration
+	// Done is set on the last event of a download, whether it succeeded or not.
+	Done bool
+}
+
+// ProgressFunc receives download progress events.
+type ProgressFunc func(progress Progress)
+
+// progressInterval limits how often ProgressFunc is called during a download.
+const progressInterval = 200 * time.Millisecond
+
+// progressReader reports bytes read from reader and throttles reading to limitRate bytes per second.
+// offset is the size of an already downloaded part, it's included in Downloaded but not in Rate.
+type progressReader struct {
+	reader     io.Reader
+	onProgress ProgressFunc
+	limitRate  int64
+
+	progress   Progress
+	offset     int64
+	startedAt  time.Time
+	reportedAt time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newProgressReader(reader io.Reader, url string, offset int64, total int64, onProgress ProgressFunc, limitRate int64) *progressReader {
+	return &progressReader{
+		reader:     reader,
+		onProgress: onProgress,
+		limitRate:  limitRate,
+		progress:   Progress{Url: url, Downloaded: offset, Total: total},
+		offset:     offset,
+		startedAt:  time.Now(),
+		now:        time.Now,
+		sleep:      time.Sleep,
+	}
+}
+
+func (r *progressReader) Read(buffer []byte) (int, error) {
+	// Small reads keep the speed even instead of bursting a large buffer and pausing
+	if r.limitRate > 0 && int64(len(buffer)) > r.limitRate/10+1 {
+		buffer = buffer[:r.limitRate/10+1]
+	}
+
+	n, err := r.reader.Read(buffer)
+	r.progress.Downloaded += int64(n)
+
+	now := r.now()
+	elapsed := now.Sub(r.startedAt)
+	received := r.progress.Downloaded - r.offset
+
+	if r.limitRate > 0 {
+		// Time the received bytes should take at the limited rate
+		expected := time.Duration(float64(received) / float64(r.limitRate) * float64(time.Second))
+		if expected > elapsed {
+			r.sleep(expected - elapsed)
+			now = r.now()
+			elapsed = now.Sub(r.startedAt)
+		}
+	}
+
+	if r.onProgress != nil && now.Sub(r.reportedAt) >= progressInterval {
+		r.reportedAt = now
+		r.onProgress(r.update(elapsed, received))
+	}
+
+	return n, err
+}
+
+// finish sends the last progress event.
+func (r *progressReader) finish() {
+	if r.onProgress == nil {
+		return
+	}
+
+	progress := r.update(r.now().Sub(r.startedAt), r.progress.Downloaded-r.offset)
+	progress.Done = true
+	r.onProgress(progress)
+}
+
+func (r *progressReader) update(elapsed time.Duration, received int64) Progress {
+	if elapsed > 0 {
+		r.progress.Rate = float64(received) / elapsed.Seconds()
+	}
+
+	r.progress.ETA = 0
+	if r.progress.Total > 0 && r.progress.Rate > 0 && r.progress.Downloaded < r.progress.Total {
+		remaining := float64(r.progress.Total - r.progress.Downloaded)
+		r.progress.ETA = time.Duration(remaining / r.progress.Rate * float64(time.Second))
+	}
+
+	return r.progress
+}
@@ -0,0 +1,103 @@
+package vuetorrent
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when sleep is called or the test moves it.
+type fakeClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(duration time.Duration) {
+	c.slept += duration
+	c.now = c.now.Add(duration)
+}
+
+func newTestProgressReader(content string, total int64, onProgress ProgressFunc, limitRate int64) (*progressReader, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	reader := newProgressReader(strings.NewReader(content), "https://example.com/vuetorrent.zip", 0, total, onProgress, limitRate)
+	reader.startedAt = clock.now
+	reader.now = clock.Now
+	reader.sleep = clock.Sleep
+	return reader, clock
+}
+
+func TestProgressReaderLimitsRate(t *testing.T) {
+	// Setup
+	reader, clock := newTestProgressReader(strings.Repeat("x", 1000), 1000, nil, 100)
+
+	// Run
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(content) != 1000 {
+		t.Errorf("Unexpected content length: %d", len(content))
+	}
+	if clock.slept < 9*time.Second || clock.slept > 11*time.Second {
+		t.Errorf("Expected about 10s of throttling at 100 B/s. Actual: %s", clock.slept)
+	}
+}
+
+func TestProgressReaderReportsProgress(t *testing.T) {
+	// Setup
+	var events []Progress
+	reader, clock := newTestProgressReader(strings.Repeat("x", 1000), 1000, func(progress Progress) {
+		events = append(events, progress)
+	}, 0)
+
+	// Run
+	buffer := make([]byte, 250)
+	for i := 0; i < 2; i++ {
+		clock.now = clock.now.Add(time.Second)
+		reader.Read(buffer)
+	}
+	reader.finish()
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events. Actual: %+v", events)
+	}
+	expected := Progress{Url: "https://example.com/vuetorrent.zip", Downloaded: 500, Total: 1000, Rate: 250, ETA: 2 * time.Second}
+	if events[1] != expected {
+		t.Errorf("\nGot: %+v\nExp: %+v", events[1], expected)
+	}
+	if !events[2].Done || events[1].Done {
+		t.Errorf("Only the last event must be done. Events: %+v", events)
+	}
+}
+
+func TestDownloadReportsProgress(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("file content"))
+	}))
+	defer server.Close()
+
+	var last Progress
+	downloader := HttpDownloader{OnProgress: func(progress Progress) {
+		last = progress
+	}}
+
+	// Run
+	_, err := downloader.Download(context.Background(), Release{Version: "1.2", DownloadUrl: server.URL}, t.TempDir())
+	if err != nil {
+		t.Fatalf("download failed. error: %s", err.Error())
+	}
+
+	if !last.Done || last.Downloaded != 12 || last.Total != 12 {
+		t.Errorf("Unexpected last progress event: %+v", last)
+	}
+}
@@ -0,0 +1,160 @@
+package vuetorrent
+
+import (
+	"context"
+	"time"
+
+	"n1kit0s/vt-manager/app/gitea"
+	"n1kit0s/vt-manager/app/github"
+	"n1kit0s/vt-manager/app/gitlab"
+)
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name        string
+	DownloadUrl string
+	// Size in bytes. Zero if the source doesn't publish it.
+	Size int64
+	// Digest in <algorithm>:<hex> form. Empty if the source doesn't publish it.
+	Digest string
+}
+
+// SourceRelease is a release as published by a ReleaseProvider, before an asset is selected.
+type SourceRelease struct {
+	TagName     string
+	Name        string
+	Draft       bool
+	Prerelease  bool
+	PublishedAt time.Time
+	Assets      []Asset
+}
+
+// ReleaseProvider is a source of VueTorrent releases (GitHub, Gitea/Forgejo, GitLab, ...).
+type ReleaseProvider interface {
+	GetReleases(ctx context.Context) ([]SourceRelease, error)
+	GetReleaseByTag(ctx context.Context, tag string) (SourceRelease, error)
+}
+
+// GithubProvider reads releases through a github.Client. Bundles implement github.Client too.
+type GithubProvider struct {
+	Client github.Client
+}
+
+func (p GithubProvider) GetReleases(ctx context.Context) ([]SourceRelease, error) {
+	githubReleases, err := p.Client.GetReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases = make([]SourceRelease, 0, len(githubReleases))
+	for _, githubRelease := range githubReleases {
+		releases = append(releases, fromGithubRelease(githubRelease))
+	}
+	return releases, nil
+}
+
+func (p GithubProvider) GetReleaseByTag(ctx context.Context, tag string) (SourceRelease, error) {
+	githubRelease, err := p.Client.GetReleaseByTag(ctx, tag)
+	if err != nil {
+		return SourceRelease{}, err
+	}
+	return fromGithubRelease(githubRelease), nil
+}
+
+func fromGithubRelease(githubRelease github.Release) SourceRelease {
+	var release = SourceRelease{
+		TagName:     githubRelease.TagName,
+		Name:        githubRelease.Name,
+		Draft:       githubRelease.Draft,
+		Prerelease:  githubRelease.Prerelease,
+		PublishedAt: githubRelease.PublishedAt,
+	}
+	for _, asset := range githubRelease.Assets {
+		release.Assets = append(release.Assets, Asset{Name: asset.Name, DownloadUrl: asset.DownloadUrl, Size: asset.Size, Digest: asset.Digest})
+	}
+	return release
+}
+
+// GiteaProvider reads releases from Gitea or Forgejo.
+type GiteaProvider struct {
+	Client gitea.Client
+}
+
+func (p GiteaProvider) GetReleases(ctx context.Context) ([]SourceRelease, error) {
+	giteaReleases, err := p.Client.GetReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases = make([]SourceRelease, 0, len(giteaReleases))
+	for _, giteaRelease := range giteaReleases {
+		releases = append(releases, fromGiteaRelease(giteaRelease))
+	}
+	return releases, nil
+}
+
+func (p GiteaProvider) GetReleaseByTag(ctx context.Context, tag string) (SourceRelease, error) {
+	giteaRelease, err := p.Client.GetReleaseByTag(ctx, tag)
+	if err != nil {
+		return SourceRelease{}, err
+	}
+	return fromGiteaRelease(giteaRelease), nil
+}
+
+func fromGiteaRelease(giteaRelease gitea.Release) SourceRelease {
+	var release = SourceRelease{
+		TagName:     giteaRelease.TagName,
+		Name:        giteaRelease.Name,
+		Draft:       giteaRelease.Draft,
+		Prerelease:  giteaRelease.Prerelease,
+		PublishedAt: giteaRelease.PublishedAt,
+	}
+	for _, asset := range giteaRelease.Assets {
+		release.Assets = append(release.Assets, Asset{Name: asset.Name, DownloadUrl: asset.DownloadUrl, Size: asset.Size})
+	}
+	return release
+}
+
+// GitlabProvider reads releases from GitLab. GitLab has no pre-release flag, so pre-releases
+// are recognized by the version suffix only. Upcoming releases are treated as drafts.
+type GitlabProvider struct {
+	Client gitlab.Client
+}
+
+func (p GitlabProvider) GetReleases(ctx context.Context) ([]SourceRelease, error) {
+	gitlabReleases, err := p.Client.GetReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases = make([]SourceRelease, 0, len(gitlabReleases))
+	for _, gitlabRelease := range gitlabReleases {
+		releases = append(releases, fromGitlabRelease(gitlabRelease))
+	}
+	return releases, nil
+}
+
+func (p GitlabProvider) GetReleaseByTag(ctx context.Context, tag string) (SourceRelease, error) {
+	gitlabRelease, err := p.Client.GetReleaseByTag(ctx, tag)
+	if err != nil {
+		return SourceRelease{}, err
+	}
+	return fromGitlabRelease(gitlabRelease), nil
+}
+
+func fromGitlabRelease(gitlabRelease gitlab.Release) SourceRelease {
+	var release = SourceRelease{
+		TagName:     gitlabRelease.TagName,
+		Name:        gitlabRelease.Name,
+		Draft:       gitlabRelease.UpcomingRelease,
+		PublishedAt: gitlabRelease.ReleasedAt,
+	}
+	for _, link := range gitlabRelease.Assets.Links {
+		var downloadUrl = link.DirectAssetUrl
+		if downloadUrl == "" {
+			downloadUrl = link.Url
+		}
+		release.Assets = append(release.Assets, Asset{Name: link.Name, DownloadUrl: downloadUrl})
+	}
+	return release
+}
@@ -0,0 +1,87 @@
+package vuetorrent
+
+import (
+	"context"
+	"n1kit0s/vt-manager/app/gitea"
+	"n1kit0s/vt-manager/app/gitlab"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockGiteaClient struct{}
+
+func (c *mockGiteaClient) GetReleases(ctx context.Context) ([]gitea.Release, error) {
+	return []gitea.Release{
+		{TagName: "v2.3.0", Assets: []gitea.Asset{{Name: "vuetorrent.zip", DownloadUrl: "https://forgejo.example.com/v2.3.0/vuetorrent.zip", Size: 10}}},
+		{TagName: "v2.4.0-beta.1", Prerelease: true, Assets: []gitea.Asset{{Name: "vuetorrent.zip", DownloadUrl: "https://forgejo.example.com/v2.4.0-beta.1/vuetorrent.zip"}}},
+		{TagName: "v2.4.0", Draft: true},
+	}, nil
+}
+
+func (c *mockGiteaClient) GetReleaseByTag(ctx context.Context, tag string) (gitea.Release, error) {
+	return gitea.Release{TagName: tag}, nil
+}
+
+type mockGitlabClient struct{}
+
+func (c *mockGitlabClient) GetReleases(ctx context.Context) ([]gitlab.Release, error) {
+	return []gitlab.Release{
+		{
+			TagName:    "v2.3.0",
+			ReleasedAt: time.Date(2023, 11, 29, 7, 28, 55, 0, time.UTC),
+			Assets: gitlab.Assets{Links: []gitlab.Link{
+				{Name: "vuetorrent.zip", Url: "https://gitlab.example.com/packages/vuetorrent.zip", DirectAssetUrl: "https://gitlab.example.com/-/releases/v2.3.0/downloads/vuetorrent.zip"},
+				{Name: "SHA256SUMS", Url: "https://gitlab.example.com/packages/SHA256SUMS"},
+			}},
+		},
+		{TagName: "v2.4.0", UpcomingRelease: true},
+	}, nil
+}
+
+func (c *mockGitlabClient) GetReleaseByTag(ctx context.Context, tag string) (gitlab.Release, error) {
+	return gitlab.Release{TagName: tag}, nil
+}
+
+func TestGiteaProvider(t *testing.T) {
+	// Setup
+	vtManager := NewVTManager(GiteaProvider{Client: &mockGiteaClient{}}, Config{Channel: ChannelPrerelease})
+
+	// Run
+	releases, err := vtManager.GetAllReleases(context.Background())
+	if err != nil {
+		t.Fatalf("Can't get releases. Error: %s", err.Error())
+	}
+
+	expected := []Release{
+		{Version: "2.3.0", DownloadUrl: "https://forgejo.example.com/v2.3.0/vuetorrent.zip", AssetName: "vuetorrent.zip", Size: 10},
+		{Version: "2.4.0-beta.1", DownloadUrl: "https://forgejo.example.com/v2.4.0-beta.1/vuetorrent.zip", AssetName: "vuetorrent.zip", Prerelease: true},
+	}
+	if !reflect.DeepEqual(expected, releases) {
+		t.Fatalf("Releases don't match. Expected: %+v | Actual: %+v", expected, releases)
+	}
+}
+
+func TestGitlabProvider(t *testing.T) {
+	// Setup
+	vtManager := NewVTManager(GitlabProvider{Client: &mockGitlabClient{}}, Config{})
+
+	// Run
+	releases, err := vtManager.GetAllReleases(context.Background())
+	if err != nil {
+		t.Fatalf("Can't get releases. Error: %s", err.Error())
+	}
+
+	expected := []Release{
+		{
+			Version:     "2.3.0",
+			DownloadUrl: "https://gitlab.example.com/-/releases/v2.3.0/downloads/vuetorrent.zip",
+			AssetName:   "vuetorrent.zip",
+			PublishedAt: time.Date(2023, 11, 29, 7, 28, 55, 0, time.UTC),
+			ChecksumUrl: "https://gitlab.example.com/packages/SHA256SUMS",
+		},
+	}
+	if !reflect.DeepEqual(expected, releases) {
+		t.Fatalf("Releases don't match. Expected: %+v | Actual: %+v", expected, releases)
+	}
+}
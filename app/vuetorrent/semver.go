@@ -0,0 +1,374 @@
+package vuetorrent
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SemVer is a semantic version as used by VueTorrent release tags (e.g. v2.3.0 or v2.4.0-beta.1).
+type SemVer struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+var semVerRegexp = regexp.MustCompile(`^[vV]?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// ParseSemVer parses a full major.minor.patch version. The "v" prefix is optional.
+func ParseSemVer(version string) (SemVer, error) {
+	match := semVerRegexp.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return SemVer{}, fmt.Errorf("invalid semantic version [%s]", version)
+	}
+
+	var semVer = SemVer{Prerelease: match[4], Build: match[5]}
+	var err error
+	if semVer.Major, err = strconv.Atoi(match[1]); err != nil {
+		return SemVer{}, fmt.Errorf("invalid major version [%s]. %s", version, err.Error())
+	}
+	if semVer.Minor, err = strconv.Atoi(match[2]); err != nil {
+		return SemVer{}, fmt.Errorf("invalid minor version [%s]. %s", version, err.Error())
+	}
+	if semVer.Patch, err = strconv.Atoi(match[3]); err != nil {
+		return SemVer{}, fmt.Errorf("invalid patch version [%s]. %s", version, err.Error())
+	}
+
+	return semVer, nil
+}
+
+func (v SemVer) String() string {
+	var version = fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		version += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		version += "+" + v.Build
+	}
+	return version
+}
+
+func (v SemVer) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or greater than other. Build metadata is ignored.
+func (v SemVer) Compare(other SemVer) int {
+	if result := compareInt(v.Major, other.Major); result != 0 {
+		return result
+	}
+	if result := compareInt(v.Minor, other.Minor); result != 0 {
+		return result
+	}
+	if result := compareInt(v.Patch, other.Patch); result != 0 {
+		return result
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+func (v SemVer) LessThan(other SemVer) bool {
+	return v.Compare(other) < 0
+}
+
+func (v SemVer) Equal(other SemVer) bool {
+	return v.Compare(other) == 0
+}
+
+func compareInt(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func comparePrerelease(a string, b string) int {
+	// A version without pre-release has higher precedence than the same version with one
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	var aIdentifiers = strings.Split(a, ".")
+	var bIdentifiers = strings.Split(b, ".")
+	for i := 0; i < len(aIdentifiers) && i < len(bIdentifiers); i++ {
+		aNum, aErr := strconv.Atoi(aIdentifiers[i])
+		bNum, bErr := strconv.Atoi(bIdentifiers[i])
+
+		var result int
+		switch {
+		case aErr == nil && bErr == nil:
+			result = compareInt(aNum, bNum)
+		case aErr == nil:
+			result = -1
+		case bErr == nil:
+			result = 1
+		default:
+			result = strings.Compare(aIdentifiers[i], bIdentifiers[i])
+		}
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return compareInt(len(aIdentifiers), len(bIdentifiers))
+}
+
+// SameVersion reports whether two version strings denote the same release.
+// Versions that are not valid semver are compared as strings.
+// validateVersion rejects versions that aren't safe to use in file and directory names,
+// e.g. a version.txt of an archive containing "../".
+func validateVersion(version string) error {
+	if _, err := ParseSemVer(version); err != nil {
+		return err
+	}
+	if filepath.Base(version) != version {
+		return fmt.Errorf("invalid version [%s]", version)
+	}
+	return nil
+}
+
+func SameVersion(a string, b string) bool {
+	aVersion, aErr := ParseSemVer(a)
+	bVersion, bErr := ParseSemVer(b)
+	if aErr != nil || bErr != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	return aVersion.Equal(bVersion)
+}
+
+type comparator struct {
+	operator string
+	version  SemVer
+}
+
+func (c comparator) check(version SemVer) bool {
+	var result = version.Compare(c.version)
+	switch c.operator {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+// Constraint is a set of version ranges joined with "||". Each range is a
+// space separated list of comparators that all have to match.
+//
+// Supported forms: 2.3.0, =2.3.0, !=2.3.0, >2.1, >=2.1 <3, ~2.3, ^2, 2.x, 2.3.*, *.
+type Constraint struct {
+	raw    string
+	ranges [][]comparator
+}
+
+var partialVersionRegexp = regexp.MustCompile(`^[vV]?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+func ParseConstraint(constraint string) (Constraint, error) {
+	var result = Constraint{raw: constraint}
+
+	for _, rangeStr := range strings.Split(constraint, "||") {
+		var comparators []comparator
+		for _, token := range splitConstraintTokens(rangeStr) {
+			parsed, err := parseComparator(token)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("invalid version constraint [%s]. %s", constraint, err.Error())
+			}
+			comparators = append(comparators, parsed...)
+		}
+
+		if len(comparators) == 0 {
+			comparators = []comparator{{operator: ">=", version: SemVer{}}}
+		}
+		result.ranges = append(result.ranges, comparators)
+	}
+
+	return result, nil
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether the version satisfies the constraint. Pre-release versions only match
+// a range that mentions a pre-release of the same major.minor.patch.
+func (c Constraint) Check(version SemVer) bool {
+	for _, comparators := range c.ranges {
+		if checkRange(comparators, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Exact returns the version if the constraint pins a single version (e.g. "2.3.0" or "=v2.3.0").
+func (c Constraint) Exact() (SemVer, bool) {
+	if len(c.ranges) != 1 || len(c.ranges[0]) != 1 || c.ranges[0][0].operator != "=" {
+		return SemVer{}, false
+	}
+	return c.ranges[0][0].version, true
+}
+
+func checkRange(comparators []comparator, version SemVer) bool {
+	var prereleaseAllowed = !version.IsPrerelease()
+	for _, comparator := range comparators {
+		if !comparator.check(version) {
+			return false
+		}
+
+		if comparator.version.IsPrerelease() &&
+			comparator.version.Major == version.Major &&
+			comparator.version.Minor == version.Minor &&
+			comparator.version.Patch == version.Patch {
+			prereleaseAllowed = true
+		}
+	}
+	return prereleaseAllowed
+}
+
+// splitConstraintTokens splits a range by spaces and commas and glues detached operators
+// to their versions, so ">= 2.1" is handled as ">=2.1".
+func splitConstraintTokens(rangeStr string) []string {
+	var fields = strings.FieldsFunc(rangeStr, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t'
+	})
+
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		if strings.Trim(fields[i], "<>=!~^") == "" && i+1 < len(fields) {
+			tokens = append(tokens, fields[i]+fields[i+1])
+			i++
+			continue
+		}
+		tokens = append(tokens, fields[i])
+	}
+	return tokens
+}
+
+func parseComparator(token string) ([]comparator, error) {
+	var operator string
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(token, op) {
+			operator = op
+			break
+		}
+	}
+
+	var versionStr = strings.TrimPrefix(token, operator)
+	match := partialVersionRegexp.FindStringSubmatch(versionStr)
+	if match == nil {
+		return nil, fmt.Errorf("invalid version [%s]", versionStr)
+	}
+
+	// Number of fixed (non wildcard) components: 0 for "*", 1 for "2.x", 2 for "2.3", 3 for "2.3.0"
+	var parts = [3]int{}
+	var fixed = 0
+	for i := 0; i < 3; i++ {
+		var part = match[i+1]
+		if part == "" || part == "x" || part == "X" || part == "*" {
+			break
+		}
+		parts[i], _ = strconv.Atoi(part)
+		fixed++
+	}
+
+	var version = SemVer{Major: parts[0], Minor: parts[1], Patch: parts[2]}
+	if fixed == 3 {
+		version.Prerelease = match[4]
+	}
+	var anyVersion = []comparator{{operator: ">=", version: SemVer{}}}
+
+	switch operator {
+	case "", "=":
+		if fixed == 0 {
+			return anyVersion, nil
+		}
+		if fixed == 3 {
+			return []comparator{{operator: "=", version: version}}, nil
+		}
+		return []comparator{{operator: ">=", version: version}, {operator: "<", version: bump(version, fixed)}}, nil
+	case "!=":
+		if fixed != 3 {
+			return nil, fmt.Errorf("operator != requires a full version [%s]", versionStr)
+		}
+		return []comparator{{operator: "!=", version: version}}, nil
+	case "~":
+		if fixed == 0 {
+			return anyVersion, nil
+		}
+		var upper = bump(version, 2)
+		if fixed == 1 {
+			upper = bump(version, 1)
+		}
+		return []comparator{{operator: ">=", version: version}, {operator: "<", version: upper}}, nil
+	case "^":
+		if fixed == 0 {
+			return anyVersion, nil
+		}
+		var upper SemVer
+		switch {
+		case version.Major > 0 || fixed == 1:
+			upper = bump(version, 1)
+		case version.Minor > 0 || fixed == 2:
+			upper = bump(version, 2)
+		default:
+			upper = bump(version, 3)
+		}
+		return []comparator{{operator: ">=", version: version}, {operator: "<", version: upper}}, nil
+	case ">":
+		if fixed == 0 {
+			return []comparator{{operator: "<", version: SemVer{}}}, nil
+		}
+		if fixed == 3 {
+			return []comparator{{operator: ">", version: version}}, nil
+		}
+		return []comparator{{operator: ">=", version: bump(version, fixed)}}, nil
+	case ">=":
+		return []comparator{{operator: ">=", version: version}}, nil
+	case "<":
+		return []comparator{{operator: "<", version: version}}, nil
+	case "<=":
+		if fixed == 0 {
+			return anyVersion, nil
+		}
+		if fixed == 3 {
+			return []comparator{{operator: "<=", version: version}}, nil
+		}
+		return []comparator{{operator: "<", version: bump(version, fixed)}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator [%s]", operator)
+}
+
+// bump increments the component at the given position (1 - major, 2 - minor, 3 - patch)
+// and resets all lower components.
+func bump(version SemVer, position int) SemVer {
+	switch position {
+	case 1:
+		return SemVer{Major: version.Major + 1}
+	case 2:
+		return SemVer{Major: version.Major, Minor: version.Minor + 1}
+	default:
+		return SemVer{Major: version.Major, Minor: version.Minor, Patch: version.Patch + 1}
+	}
+}
@@ -0,0 +1,169 @@
+package vuetorrent
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParseSemVer(t *testing.T) {
+	tests := map[string]struct {
+		version       string
+		expected      SemVer
+		expectedError bool
+	}{
+		"plain version":          {version: "2.3.0", expected: SemVer{Major: 2, Minor: 3, Patch: 0}},
+		"version with v prefix":  {version: "v2.3.1", expected: SemVer{Major: 2, Minor: 3, Patch: 1}},
+		"pre-release version":    {version: "v2.4.0-beta.1", expected: SemVer{Major: 2, Minor: 4, Prerelease: "beta.1"}},
+		"version with build":     {version: "1.0.0+20231129", expected: SemVer{Major: 1, Build: "20231129"}},
+		"version with new line":  {version: "2.3.0\n", expected: SemVer{Major: 2, Minor: 3}},
+		"partial version":        {version: "2.3", expectedError: true},
+		"not a version":          {version: "latest", expectedError: true},
+		"empty version":          {version: "", expectedError: true},
+		"wildcard is not exact":  {version: "2.x.0", expectedError: true},
+		"negative numbers error": {version: "-1.0.0", expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ParseSemVer(test.version)
+			if test.expectedError {
+				if err == nil {
+					t.Fatalf("Expected error for [%s]. Got: %+v", test.version, actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseSemVer failed. Error: %s", err.Error())
+			}
+			if actual != test.expected {
+				t.Fatalf("Expected: %+v | Actual: %+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSemVerOrdering(t *testing.T) {
+	// Setup
+	expectedOrder := []string{
+		"0.9.0",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.2.0",
+		"1.10.0",
+		"2.0.0",
+	}
+
+	var versions []SemVer
+	for i := len(expectedOrder) - 1; i >= 0; i-- {
+		version, err := ParseSemVer(expectedOrder[i])
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		versions = append(versions, version)
+	}
+
+	// Run
+	sort.Slice(versions, func(i, j int) bool { return versions[i].LessThan(versions[j]) })
+
+	for i, version := range versions {
+		if version.String() != expectedOrder[i] {
+			t.Errorf("Position %d. Expected: %s | Actual: %s", i, expectedOrder[i], version.String())
+		}
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := map[string]struct {
+		constraint string
+		matches    []string
+		mismatches []string
+	}{
+		"exact":            {constraint: "2.3.0", matches: []string{"2.3.0", "v2.3.0"}, mismatches: []string{"2.3.1", "2.2.0"}},
+		"exact with v":     {constraint: "=v2.3.0", matches: []string{"2.3.0"}, mismatches: []string{"2.3.1"}},
+		"not equal":        {constraint: "!=2.3.0", matches: []string{"2.3.1", "2.2.0"}, mismatches: []string{"2.3.0"}},
+		"tilde minor":      {constraint: "~2.3", matches: []string{"2.3.0", "2.3.9"}, mismatches: []string{"2.4.0", "2.2.9"}},
+		"tilde patch":      {constraint: "~2.3.1", matches: []string{"2.3.1", "2.3.5"}, mismatches: []string{"2.3.0", "2.4.0"}},
+		"tilde major":      {constraint: "~2", matches: []string{"2.0.0", "2.9.9"}, mismatches: []string{"3.0.0", "1.9.0"}},
+		"caret major":      {constraint: "^2", matches: []string{"2.0.0", "2.9.9"}, mismatches: []string{"3.0.0", "1.9.0"}},
+		"caret full":       {constraint: "^2.1.3", matches: []string{"2.1.3", "2.9.0"}, mismatches: []string{"2.1.2", "3.0.0"}},
+		"caret zero minor": {constraint: "^0.4.2", matches: []string{"0.4.2", "0.4.9"}, mismatches: []string{"0.5.0", "0.4.1"}},
+		"caret zero patch": {constraint: "^0.0.3", matches: []string{"0.0.3"}, mismatches: []string{"0.0.4", "0.1.0"}},
+		"range":            {constraint: ">=2.1 <3", matches: []string{"2.1.0", "2.9.9"}, mismatches: []string{"2.0.9", "3.0.0"}},
+		"range with space": {constraint: ">= 2.1, < 3", matches: []string{"2.1.0"}, mismatches: []string{"3.0.0"}},
+		"greater partial":  {constraint: ">2.1", matches: []string{"2.2.0"}, mismatches: []string{"2.1.9"}},
+		"less or equal":    {constraint: "<=2.1", matches: []string{"2.1.9", "1.0.0"}, mismatches: []string{"2.2.0"}},
+		"x range":          {constraint: "2.x", matches: []string{"2.0.0", "2.7.1"}, mismatches: []string{"3.0.0", "1.9.9"}},
+		"star range":       {constraint: "2.3.*", matches: []string{"2.3.0", "2.3.4"}, mismatches: []string{"2.4.0"}},
+		"any":              {constraint: "*", matches: []string{"0.0.1", "9.9.9"}},
+		"or":               {constraint: "1.x || >=2.3", matches: []string{"1.5.0", "2.3.0"}, mismatches: []string{"2.2.0"}},
+		"skip prereleases": {constraint: "^2", matches: []string{"2.4.0"}, mismatches: []string{"2.4.0-beta.1"}},
+		"allow prerelease": {constraint: ">=2.4.0-beta.1", matches: []string{"2.4.0-beta.2", "2.4.0", "2.5.0"}, mismatches: []string{"2.4.0-alpha", "2.5.0-beta.1"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			constraint, err := ParseConstraint(test.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint failed. Error: %s", err.Error())
+			}
+
+			for _, version := range test.matches {
+				if !constraint.Check(mustParseSemVer(t, version)) {
+					t.Errorf("Expected [%s] to match [%s]", version, test.constraint)
+				}
+			}
+			for _, version := range test.mismatches {
+				if constraint.Check(mustParseSemVer(t, version)) {
+					t.Errorf("Expected [%s] not to match [%s]", version, test.constraint)
+				}
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, constraint := range []string{"latest", "2.3.0.1", ">=foo", "!=2.x"} {
+		if _, err := ParseConstraint(constraint); err == nil {
+			t.Errorf("Expected error for constraint [%s]", constraint)
+		}
+	}
+}
+
+func TestConstraintExact(t *testing.T) {
+	tests := map[string]bool{
+		"2.3.0":          true,
+		"v2.3.0":         true,
+		"=2.3.0":         true,
+		"2.4.0-beta.1":   true,
+		"2.3":            false,
+		"~2.3.0":         false,
+		">=2.3.0":        false,
+		"2.3.0 || 2.4.0": false,
+	}
+
+	for constraintStr, expected := range tests {
+		constraint, err := ParseConstraint(constraintStr)
+		if err != nil {
+			t.Fatalf("ParseConstraint failed. Error: %s", err.Error())
+		}
+
+		if _, exact := constraint.Exact(); exact != expected {
+			t.Errorf("Constraint [%s]. Expected exact: %t | Actual: %t", constraintStr, expected, exact)
+		}
+	}
+}
+
+func mustParseSemVer(t *testing.T, version string) SemVer {
+	semVer, err := ParseSemVer(version)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return semVer
+}
@@ -0,0 +1,160 @@
+package vuetorrent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// maxSignatureSize limits downloaded signature assets, they are a few hundred bytes.
+const maxSignatureSize = 64 * 1024
+
+// signatureExtensions are suffixes of detached signature assets published next to the archive.
+var signatureExtensions = []string{".minisig", ".sig", ".asc"}
+
+// SignatureExtension returns the signature extension (e.g. ".minisig") the url or file name ends with.
+// It's empty if the url is not a signature.
+func SignatureExtension(signatureUrl string) string {
+	for _, extension := range signatureExtensions {
+		if strings.HasSuffix(strings.ToLower(signatureUrl), extension) {
+			return extension
+		}
+	}
+	return ""
+}
+
+// isSignatureAsset reports whether the asset is a detached signature of the archive asset.
+func isSignatureAsset(assetName string, archiveName string) bool {
+	for _, extension := range signatureExtensions {
+		if strings.EqualFold(assetName, archiveName+extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignatureVerifier checks detached signatures of release archives.
+type SignatureVerifier interface {
+	// Extensions are the suffixes of signature assets the verifier can read (e.g. ".minisig").
+	Extensions() []string
+	// Verify returns an error unless signature is a valid signature of the file by a trusted key.
+	Verify(filePath string, signature []byte) error
+}
+
+// LoadSignatureVerifier reads a minisign public key or an OpenPGP public key ring (armored or binary).
+func LoadSignatureVerifier(keyFile string) (SignatureVerifier, error) {
+	content, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature key %s. %s", keyFile, err.Error())
+	}
+
+	if publicKey, err := ParseMinisignPublicKey(string(content)); err == nil {
+		return MinisignVerifier{PublicKey: publicKey}, nil
+	}
+
+	verifier, err := NewOpenPGPVerifier(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a minisign nor an OpenPGP public key. %s", keyFile, err.Error())
+	}
+	return verifier, nil
+}
+
+// verifySignature checks the archive against its signature asset. A release without a signature
+// for the verifier is only accepted if signatures aren't required.
+func (mng *vtManager) verifySignature(ctx context.Context, filePath string, release Release) error {
+	verifier := mng.config.SignatureVerifier
+	if verifier == nil {
+		if mng.config.RequireSignature {
+			return fmt.Errorf("signature is required, but no signature key is configured")
+		}
+		return nil
+	}
+
+	signatureUrl := findSignatureUrl(release.SignatureUrls, verifier.Extensions())
+	if signatureUrl == "" {
+		if mng.config.RequireSignature {
+			return fmt.Errorf("release %s has no signature (%s). Installation refused", release.Version, strings.Join(verifier.Extensions(), ", "))
+		}
+		slog.Warn("No signature published for release. Skipping signature verification", "version", release.Version)
+		return nil
+	}
+
+	signature, err := DownloadSignature(ctx, signatureUrl, mng.config.HttpClient)
+	if err != nil {
+		return err
+	}
+
+	if err := verifier.Verify(filePath, signature); err != nil {
+		return fmt.Errorf("signature verification of %s failed. %s", filePath, err.Error())
+	}
+
+	slog.Info("Archive signature verified", "signature", signatureUrl)
+	return nil
+}
+
+func findSignatureUrl(signatureUrls []string, extensions []string) string {
+	for _, extension := range extensions {
+		for _, signatureUrl := range signatureUrls {
+			if strings.HasSuffix(strings.ToLower(signatureUrl), extension) {
+				return signatureUrl
+			}
+		}
+	}
+	return ""
+}
+
+// DownloadSignature returns the content of a signature asset. file:// urls (e.g. from a release bundle) are read directly.
+func DownloadSignature(ctx context.Context, signatureUrl string, client *http.Client) ([]byte, error) {
+	if parsedUrl, err := url.Parse(signatureUrl); err == nil && parsedUrl.Scheme == "file" {
+		return readSignature(parsedUrl.Path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", signatureUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient(client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download signature. %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if err := httpclient.CheckStatus(resp, nil); err != nil {
+		return nil, fmt.Errorf("failed to download signature from %s. %w", signatureUrl, err)
+	}
+
+	signature, err := io.ReadAll(io.LimitReader(resp.Body, maxSignatureSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download signature from %s. %s", signatureUrl, err.Error())
+	}
+	if len(signature) > maxSignatureSize {
+		return nil, fmt.Errorf("signature %s exceeds %d bytes", signatureUrl, maxSignatureSize)
+	}
+
+	return signature, nil
+}
+
+func readSignature(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature. %s", err.Error())
+	}
+	defer file.Close()
+
+	signature, err := io.ReadAll(io.LimitReader(file, maxSignatureSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature %s. %s", filePath, err.Error())
+	}
+	if len(signature) > maxSignatureSize {
+		return nil, fmt.Errorf("signature %s exceeds %d bytes", filePath, maxSignatureSize)
+	}
+	return signature, nil
+}
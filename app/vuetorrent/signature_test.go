@@ -0,0 +1,330 @@
+package vuetorrent
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"n1kit0s/vt-manager/app/github"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"golang.org/x/crypto/blake2b"
+)
+
+type minisignKey struct {
+	keyId      [8]byte
+	privateKey ed25519.PrivateKey
+	publicKey  MinisignPublicKey
+}
+
+func newMinisignKey(t *testing.T, keyId byte) minisignKey {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var key = minisignKey{keyId: [8]byte{keyId, 2, 3, 4, 5, 6, 7, 8}, privateKey: privateKey}
+	key.publicKey = MinisignPublicKey{KeyId: key.keyId, Key: publicKey}
+	return key
+}
+
+// publicKeyFile returns the key as written by minisign -G.
+func (k minisignKey) publicKeyFile() string {
+	encoded := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), k.keyId[:]...), k.publicKey.Key...))
+	return fmt.Sprintf("untrusted comment: minisign public key %X\n%s\n", reverse(k.keyId[:]), encoded)
+}
+
+// sign creates a signature like minisign -S. algorithm is "ED" (hashed) or "Ed" (legacy).
+func (k minisignKey) sign(t *testing.T, content []byte, algorithm string, trustedComment string) []byte {
+	var message = content
+	if algorithm == minisignHashedAlgorithm {
+		hash := blake2b.Sum512(content)
+		message = hash[:]
+	}
+
+	signature := ed25519.Sign(k.privateKey, message)
+	globalSignature := ed25519.Sign(k.privateKey, append(append([]byte{}, signature...), trustedComment...))
+
+	var result strings.Builder
+	result.WriteString("untrusted comment: signature from minisign secret key\n")
+	result.WriteString(base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), k.keyId[:]...), signature...)) + "\n")
+	result.WriteString(minisignTrustedComment + trustedComment + "\n")
+	result.WriteString(base64.StdEncoding.EncodeToString(globalSignature) + "\n")
+	return []byte(result.String())
+}
+
+func writeArchive(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "vuetorrent.zip")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	return filePath
+}
+
+func TestMinisignVerify(t *testing.T) {
+	// Setup
+	key := newMinisignKey(t, 1)
+	otherKey := newMinisignKey(t, 9)
+	filePath := writeArchive(t, "archive content")
+
+	tamperedComment := key.sign(t, []byte("archive content"), minisignHashedAlgorithm, "timestamp:1")
+	tamperedComment = bytes.Replace(tamperedComment, []byte("timestamp:1"), []byte("timestamp:2"), 1)
+
+	tests := map[string]struct {
+		signature     []byte
+		expectedError bool
+	}{
+		"hashed signature":         {signature: key.sign(t, []byte("archive content"), minisignHashedAlgorithm, "file:vuetorrent.zip")},
+		"legacy signature":         {signature: key.sign(t, []byte("archive content"), minisignAlgorithm, "file:vuetorrent.zip")},
+		"other content":            {signature: key.sign(t, []byte("other content"), minisignHashedAlgorithm, ""), expectedError: true},
+		"other key":                {signature: otherKey.sign(t, []byte("archive content"), minisignHashedAlgorithm, ""), expectedError: true},
+		"tampered trusted comment": {signature: tamperedComment, expectedError: true},
+		"not a signature":          {signature: []byte("<html>Not Found</html>"), expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Run
+			err := MinisignVerifier{PublicKey: key.publicKey}.Verify(filePath, test.signature)
+
+			if test.expectedError && err == nil {
+				t.Error("Expected verification to fail")
+			}
+			if !test.expectedError && err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestParseMinisignPublicKey(t *testing.T) {
+	// Setup
+	key := newMinisignKey(t, 1)
+	lines := strings.Split(key.publicKeyFile(), "\n")
+
+	for _, text := range []string{key.publicKeyFile(), lines[1]} {
+		// Run
+		publicKey, err := ParseMinisignPublicKey(text)
+		if err != nil {
+			t.Fatalf("Failed to parse public key. Error: %s", err.Error())
+		}
+
+		if publicKey.KeyId != key.keyId || !publicKey.Key.Equal(key.publicKey.Key) {
+			t.Errorf("Unexpected public key: %+v", publicKey)
+		}
+	}
+}
+
+func newOpenPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("VueTorrent", "", "release@vuetorrent.test", nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var publicKey bytes.Buffer
+	writer, _ := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	if err := entity.Serialize(writer); err != nil {
+		t.Fatal(err.Error())
+	}
+	writer.Close()
+
+	return entity, publicKey.String()
+}
+
+func TestOpenPGPVerify(t *testing.T) {
+	// Setup
+	entity, publicKey := newOpenPGPKey(t)
+	otherEntity, _ := newOpenPGPKey(t)
+	filePath := writeArchive(t, "archive content")
+
+	sign := func(signer *openpgp.Entity, content string, armored bool) []byte {
+		var signature bytes.Buffer
+		var err error
+		if armored {
+			err = openpgp.ArmoredDetachSign(&signature, signer, strings.NewReader(content), nil)
+		} else {
+			err = openpgp.DetachSign(&signature, signer, strings.NewReader(content), nil)
+		}
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return signature.Bytes()
+	}
+
+	verifier, err := NewOpenPGPVerifier(strings.NewReader(publicKey))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := map[string]struct {
+		signature     []byte
+		expectedError bool
+	}{
+		"armored signature": {signature: sign(entity, "archive content", true)},
+		"binary signature":  {signature: sign(entity, "archive content", false)},
+		"other content":     {signature: sign(entity, "other content", true), expectedError: true},
+		"other key":         {signature: sign(otherEntity, "archive content", true), expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Run
+			err := verifier.Verify(filePath, test.signature)
+
+			if test.expectedError && err == nil {
+				t.Error("Expected verification to fail")
+			}
+			if !test.expectedError && err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestLoadSignatureVerifier(t *testing.T) {
+	// Setup
+	_, openPGPKey := newOpenPGPKey(t)
+	keyDir := t.TempDir()
+	os.WriteFile(filepath.Join(keyDir, "minisign.pub"), []byte(newMinisignKey(t, 1).publicKeyFile()), 0644)
+	os.WriteFile(filepath.Join(keyDir, "release.asc"), []byte(openPGPKey), 0644)
+	os.WriteFile(filepath.Join(keyDir, "invalid.pub"), []byte("not a key"), 0644)
+
+	tests := map[string]struct {
+		keyFile            string
+		expectedExtensions string
+		expectedError      bool
+	}{
+		"minisign": {keyFile: "minisign.pub", expectedExtensions: ".minisig"},
+		"openpgp":  {keyFile: "release.asc", expectedExtensions: ".asc .sig"},
+		"invalid":  {keyFile: "invalid.pub", expectedError: true},
+		"missing":  {keyFile: "missing.pub", expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Run
+			verifier, err := LoadSignatureVerifier(filepath.Join(keyDir, test.keyFile))
+
+			if test.expectedError {
+				if err == nil {
+					t.Error("Expected loading to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if extensions := strings.Join(verifier.Extensions(), " "); extensions != test.expectedExtensions {
+				t.Errorf("Unexpected verifier with extensions [%s]", extensions)
+			}
+		})
+	}
+}
+
+// signedGithubClient returns a release with the archive and the given signature assets.
+type signedGithubClient struct {
+	signatureAssets []github.Asset
+}
+
+func (c *signedGithubClient) GetReleases(ctx context.Context) ([]github.Release, error) {
+	release, err := c.GetReleaseByTag(ctx, "v1.1.2")
+	return []github.Release{release}, err
+}
+
+func (c *signedGithubClient) GetReleaseByTag(ctx context.Context, tag string) (github.Release, error) {
+	var assets = []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent.zip"}}
+	return github.Release{TagName: tag, Assets: append(assets, c.signatureAssets...)}, nil
+}
+
+func TestInstallVerifiesSignature(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{{Path: "vuetorrent/public/index.html", Content: "index"}})
+	archive, _ := os.ReadFile(archivePath)
+	key := newMinisignKey(t, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/valid.minisig":
+			w.Write(key.sign(t, archive, minisignHashedAlgorithm, "file:vuetorrent.zip"))
+		case "/invalid.minisig":
+			w.Write(key.sign(t, []byte("other archive"), minisignHashedAlgorithm, "file:vuetorrent.zip"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		signatureUrl     string
+		requireSignature bool
+		expectedError    bool
+	}{
+		"valid signature":              {signatureUrl: server.URL + "/valid.minisig"},
+		"invalid signature":            {signatureUrl: server.URL + "/invalid.minisig", expectedError: true},
+		"missing signature asset":      {signatureUrl: server.URL + "/missing.minisig", expectedError: true},
+		"unsigned release":             {},
+		"unsigned release is required": {requireSignature: true, expectedError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var client = &signedGithubClient{}
+			if test.signatureUrl != "" {
+				client.signatureAssets = []github.Asset{{Name: "vuetorrent.zip.minisig", DownloadUrl: test.signatureUrl}}
+			}
+			vtManager := vtManager{
+				provider:   GithubProvider{Client: client},
+				downloader: fixedPathDownloader{filePath: archivePath},
+				unzipper:   DefaultUnzipper{},
+				config: Config{
+					SignatureVerifier: MinisignVerifier{PublicKey: key.publicKey},
+					RequireSignature:  test.requireSignature,
+				},
+			}
+			outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+
+			// Run
+			_, err := vtManager.Install(context.Background(), "1.1.2", outputDir)
+
+			if test.expectedError {
+				if err == nil {
+					t.Error("Expected installation to fail")
+				}
+				if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+					t.Errorf("Archive was extracted without a valid signature")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Installation failed. Error: %s", err.Error())
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, "public", "index.html")); err != nil {
+				t.Errorf("Archive was not installed")
+			}
+		})
+	}
+}
+
+func TestRequireSignatureWithoutKey(t *testing.T) {
+	// Setup
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: fixedPathDownloader{filePath: writeArchive(t, "archive content")},
+		unzipper:   mockUnziper{},
+		config:     Config{RequireSignature: true},
+	}
+
+	// Run
+	_, err := vtManager.Install(context.Background(), "1.1.2", filepath.Join(t.TempDir(), "vuetorrent"))
+
+	if err == nil {
+		t.Error("Expected installation to fail without a signature key")
+	}
+}
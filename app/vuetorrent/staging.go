@@ -0,0 +1,109 @@
+package vuetorrent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// newStagingDir creates a unique directory next to outputDir, so the final swap is a rename
+// within the same filesystem.
+func newStagingDir(outputDir string) (string, error) {
+	stagingDir, err := os.MkdirTemp(filepath.Dir(outputDir), "."+filepath.Base(outputDir)+"-staging-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory. %s", err.Error())
+	}
+
+	// MkdirTemp creates a private directory, but qBittorrent may run as another user
+	if err := os.Chmod(stagingDir, 0755); err != nil {
+		os.Remove(stagingDir)
+		return "", err
+	}
+	return stagingDir, nil
+}
+
+func (mng *vtManager) extractToStaging(ctx context.Context, archivePath string, stagingDir string, release Release) error {
+	slog.Info("Extracting release into staging directory", "stagingDir", stagingDir)
+	if err := mng.unzipper.Unzip(ctx, archivePath, stagingDir); err != nil {
+		return fmt.Errorf("failed to extract %s. %s", archivePath, err.Error())
+	}
+
+	if err := createVersionFile(release.Version, stagingDir); err != nil {
+		return fmt.Errorf("failed to create version file. %s", err.Error())
+	}
+
+	// The archive was verified by the downloader, keep its hash next to version.txt.
+	// It's named after the release asset, the cached file name is an implementation detail
+	assetName := release.AssetName
+	if assetName == "" {
+		assetName = filepath.Base(archivePath)
+	}
+	if digest, err := fileDigest(archivePath, "sha256"); err == nil {
+		if err := writeChecksumFile(digest, assetName, stagingDir); err != nil {
+			slog.Warn("Can't create checksum file", "error", err.Error())
+		}
+	} else {
+		slog.Warn("Can't calculate archive checksum", "error", err.Error())
+	}
+
+	return verifyStagedInstall(stagingDir, release.Version)
+}
+
+func verifyStagedInstall(stagingDir string, version string) error {
+	entries, err := os.ReadDir(stagingDir)
+	if err != nil {
+		return fmt.Errorf("failed to read staging directory %s. %s", stagingDir, err.Error())
+	}
+
+	// version.txt is always there, so a valid release has at least one more entry
+	if len(entries) < 2 {
+		return fmt.Errorf("staging directory %s doesn't contain release files", stagingDir)
+	}
+
+	stagedVersion, err := GetInstalledVersion(stagingDir)
+	if err != nil {
+		return fmt.Errorf("failed to read staged version. %s", err.Error())
+	}
+	if !SameVersion(stagedVersion, version) {
+		slog.Warn("Staged version differs from release version", "stagedVersion", stagedVersion, "releaseVersion", version)
+	}
+
+	slog.Info("Staged release verified", "stagingDir", stagingDir)
+	return nil
+}
+
+// swapInstall moves the current installation into the backup store and renames the staging
+// directory into its place. If the second rename fails the previous installation is restored.
+func swapInstall(stagingDir string, outputDir string, backups BackupStore) (Backup, error) {
+	backup, err := backups.Save(outputDir)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to move previous version aside. %s", err.Error())
+	}
+
+	slog.Info("Moving staged release into place", "from", stagingDir, "to", outputDir)
+	if err := os.Rename(stagingDir, outputDir); err != nil {
+		restoreBackup(backup.Path, outputDir)
+		return Backup{}, fmt.Errorf("failed to move staged release into %s. %s", outputDir, err.Error())
+	}
+
+	return backup, nil
+}
+
+func restoreBackup(backupDir string, outputDir string) {
+	if backupDir == "" {
+		return
+	}
+
+	slog.Warn("Restoring previous version", "backupDir", backupDir, "dir", outputDir)
+	if err := os.RemoveAll(outputDir); err != nil {
+		slog.Error("Can't remove failed installation", "dir", outputDir, "error", err.Error())
+		return
+	}
+	if err := moveDir(backupDir, outputDir); err != nil {
+		slog.Error("Can't restore previous version", "backupDir", backupDir, "error", err.Error())
+		return
+	}
+	slog.Info("Previous version restored", "dir", outputDir)
+}
@@ -0,0 +1,158 @@
+package vuetorrent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type failingUnzipper struct{}
+
+func (m failingUnzipper) Unzip(ctx context.Context, filePath string, outputDir string) error {
+	os.MkdirAll(outputDir, os.ModePerm)
+	os.WriteFile(filepath.Join(outputDir, "half-written.js"), []byte("..."), 0644)
+	return fmt.Errorf("unexpected EOF")
+}
+
+func createInstalledVersion(t *testing.T, dir string, version string) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "version.txt"), []byte(version), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(version), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestInstallReplacesPreviousVersion(t *testing.T) {
+	// Setup
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
+	}
+	tempDir := t.TempDir()
+	outputDir := filepath.Join(tempDir, "vuetorrent")
+	createInstalledVersion(t, outputDir, "1.1.1")
+
+	// Run
+	_, err := vtManager.Install(context.Background(), "1.1.2", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	version, _ := GetInstalledVersion(outputDir)
+	if version != "1.1.2" {
+		t.Errorf("Expected version 1.1.2. Actual: %s", version)
+	}
+
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only installation directory to be left. Actual entries: %v", entries)
+	}
+}
+
+func TestInstallDoesNotTouchExistingStagingDir(t *testing.T) {
+	// Setup
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
+	}
+	tempDir := t.TempDir()
+	outputDir := filepath.Join(tempDir, "vuetorrent")
+	// A directory named like the staging directory of the old implementation
+	unrelatedDir := filepath.Join(tempDir, ".vuetorrent-staging-1.1.2")
+	if err := os.MkdirAll(unrelatedDir, os.ModePerm); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(unrelatedDir, "data"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Run
+	if _, err := vtManager.Install(context.Background(), "1.1.2", outputDir); err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	if _, err := os.Stat(filepath.Join(unrelatedDir, "data")); err != nil {
+		t.Fatalf("Existing directory was changed. Error: %s", err.Error())
+	}
+	info, err := os.Stat(outputDir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("Unexpected installation directory permissions %s", info.Mode().Perm())
+	}
+}
+
+func TestInstallRestoresPreviousVersionOnUnzipFailure(t *testing.T) {
+	// Setup
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   failingUnzipper{},
+	}
+	tempDir := t.TempDir()
+	outputDir := filepath.Join(tempDir, "vuetorrent")
+	createInstalledVersion(t, outputDir, "1.1.1")
+
+	// Run
+	_, err := vtManager.Install(context.Background(), "1.1.2", outputDir)
+	if err == nil {
+		t.Fatal("Expected installation to fail")
+	}
+
+	version, _ := GetInstalledVersion(outputDir)
+	if version != "1.1.1" {
+		t.Errorf("Previous version was not kept. Actual version: %s", version)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "half-written.js")); err == nil {
+		t.Errorf("Partially extracted files leaked into %s", outputDir)
+	}
+
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 1 {
+		t.Errorf("Staging directory was not cleaned up. Actual entries: %v", entries)
+	}
+}
+
+func TestSwapInstallRestoresBackupWhenRenameFails(t *testing.T) {
+	// Setup
+	tempDir := t.TempDir()
+	outputDir := filepath.Join(tempDir, "vuetorrent")
+	createInstalledVersion(t, outputDir, "1.1.1")
+	missingStagingDir := filepath.Join(tempDir, "missing-staging")
+
+	// Run
+	_, err := swapInstall(missingStagingDir, outputDir, NewBackupStore(outputDir, "", 0))
+	if err == nil {
+		t.Fatal("Expected swap to fail")
+	}
+
+	version, _ := GetInstalledVersion(outputDir)
+	if version != "1.1.1" {
+		t.Errorf("Previous version was not restored. Actual version: %s", version)
+	}
+	if _, err := os.Stat(filepath.Join(DefaultBackupDir(outputDir), "1.1.1")); !os.IsNotExist(err) {
+		t.Errorf("Backup directory should be moved back")
+	}
+}
+
+func TestVerifyStagedInstallRejectsEmptyRelease(t *testing.T) {
+	// Setup
+	stagingDir := t.TempDir()
+	createVersionFile("1.1.1", stagingDir)
+
+	// Run
+	err := verifyStagedInstall(stagingDir, "1.1.1")
+	if err == nil {
+		t.Fatal("Expected empty release to be rejected")
+	}
+}
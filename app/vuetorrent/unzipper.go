@@ -2,6 +2,7 @@ package vuetorrent
 
 import (
 	"archive/zip"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
@@ -10,13 +11,26 @@ import (
 	"strings"
 )
 
+const (
+	DefaultMaxExtractSize  int64 = 200 * 1024 * 1024
+	DefaultMaxExtractFiles       = 10000
+)
+
 type Unzipper interface {
-	Unzip(filePath string, outputDir string) error
+	Unzip(ctx context.Context, filePath string, outputDir string) error
 }
 
-type DefaultUnzipper struct{}
+// DefaultUnzipper extracts VueTorrent archives. Entries that would be written outside of
+// the output directory, absolute paths and symlinks are rejected. Extraction stops between
+// entries once ctx is cancelled, the partially extracted files are left to the caller.
+type DefaultUnzipper struct {
+	// MaxTotalSize limits the total uncompressed size in bytes. Zero means DefaultMaxExtractSize.
+	MaxTotalSize int64
+	// MaxFiles limits the number of entries in the archive. Zero means DefaultMaxExtractFiles.
+	MaxFiles int
+}
 
-func (u DefaultUnzipper) Unzip(filePath string, outputDir string) error {
+func (u DefaultUnzipper) Unzip(ctx context.Context, filePath string, outputDir string) error {
 	slog.Info(fmt.Sprintf("Extracting %s into %s", filePath, outputDir))
 
 	_, err := os.Open(outputDir)
@@ -33,13 +47,27 @@ func (u DefaultUnzipper) Unzip(filePath string, outputDir string) error {
 	}
 	defer archive.Close()
 
+	if err := u.checkLimits(archive.File); err != nil {
+		return err
+	}
+
+	var cleanedOutputDir = filepath.Clean(outputDir)
+	var remainingSize = u.maxTotalSize()
+
 	for _, file := range archive.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		fileName, _ := strings.CutPrefix(file.Name, "vuetorrent/")
 		if fileName == "" {
 			continue
 		}
 
-		filePath = filepath.Join(filepath.Clean(outputDir), fileName)
+		filePath, err := safeExtractPath(cleanedOutputDir, file)
+		if err != nil {
+			return err
+		}
 
 		if file.FileInfo().IsDir() {
 			if err := os.MkdirAll(filePath, os.ModePerm); err != nil {
@@ -52,23 +80,94 @@ func (u DefaultUnzipper) Unzip(filePath string, outputDir string) error {
 			return err
 		}
 
-		dstFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
+		written, err := extractFile(file, filePath, remainingSize)
 		if err != nil {
 			return err
 		}
+		remainingSize -= written
+	}
 
-		fileInArchive, err := file.Open()
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		if _, err := io.Copy(dstFile, fileInArchive); err != nil {
-			return err
-		}
+func (u DefaultUnzipper) maxTotalSize() int64 {
+	if u.MaxTotalSize > 0 {
+		return u.MaxTotalSize
+	}
+	return DefaultMaxExtractSize
+}
+
+func (u DefaultUnzipper) maxFiles() int {
+	if u.MaxFiles > 0 {
+		return u.MaxFiles
+	}
+	return DefaultMaxExtractFiles
+}
 
-		dstFile.Close()
-		fileInArchive.Close()
+// checkLimits rejects archives whose declared entry count or uncompressed size is too big.
+// The declared size can be forged, so extractFile enforces the limit again while writing.
+func (u DefaultUnzipper) checkLimits(files []*zip.File) error {
+	if len(files) > u.maxFiles() {
+		return fmt.Errorf("archive has %d entries. limit is %d", len(files), u.maxFiles())
+	}
+
+	var totalSize uint64
+	for _, file := range files {
+		totalSize += file.UncompressedSize64
+		if totalSize > uint64(u.maxTotalSize()) {
+			return fmt.Errorf("archive uncompressed size exceeds limit of %d bytes", u.maxTotalSize())
+		}
 	}
 
 	return nil
 }
+
+// safeExtractPath returns the destination of the zip entry and makes sure it stays inside outputDir.
+func safeExtractPath(outputDir string, file *zip.File) (string, error) {
+	fileName, _ := strings.CutPrefix(file.Name, "vuetorrent/")
+
+	if strings.HasPrefix(fileName, "/") || strings.HasPrefix(fileName, `\`) || filepath.IsAbs(fileName) || filepath.VolumeName(fileName) != "" {
+		return "", fmt.Errorf("zip entry [%s] has an absolute path", file.Name)
+	}
+
+	mode := file.Mode()
+	if mode&os.ModeSymlink != 0 {
+		return "", fmt.Errorf("zip entry [%s] is a symlink. symlinks are not allowed", file.Name)
+	}
+	if !mode.IsDir() && !mode.IsRegular() {
+		return "", fmt.Errorf("zip entry [%s] is not a regular file", file.Name)
+	}
+
+	filePath := filepath.Join(outputDir, fileName)
+	relPath, err := filepath.Rel(outputDir, filePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("zip entry [%s] escapes output directory", file.Name)
+	}
+
+	return filePath, nil
+}
+
+func extractFile(file *zip.File, filePath string, remainingSize int64) (int64, error) {
+	dstFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+	defer dstFile.Close()
+
+	fileInArchive, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer fileInArchive.Close()
+
+	// Read one byte over the limit to find out if the entry is bigger than declared
+	written, err := io.Copy(dstFile, io.LimitReader(fileInArchive, remainingSize+1))
+	if err != nil {
+		return written, err
+	}
+	if written > remainingSize {
+		return written, fmt.Errorf("archive uncompressed size exceeds limit while extracting [%s]", file.Name)
+	}
+
+	return written, nil
+}
@@ -1,6 +1,9 @@
 package vuetorrent
 
 import (
+	"archive/zip"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,7 +27,7 @@ func TestUnzip(t *testing.T) {
 	unzipper := DefaultUnzipper{}
 
 	// Run
-	err := unzipper.Unzip(archivePath, outputDir)
+	err := unzipper.Unzip(context.Background(), archivePath, outputDir)
 	if err != nil {
 		t.Fatalf("Failed to unzip. Error: %s", err.Error())
 	}
@@ -37,3 +40,111 @@ func TestUnzip(t *testing.T) {
 		}
 	}
 }
+
+func TestUnzipStopsOnCancel(t *testing.T) {
+	// Setup
+	archivePath := createZip(t, []TestFile{
+		{Path: "vuetorrent/version.txt", Content: "1.2.3"},
+		{Path: "vuetorrent/public/index.html", Content: "index"},
+	})
+	outputDir := filepath.Join(t.TempDir(), "test_out")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Run
+	err := DefaultUnzipper{}.Unzip(ctx, archivePath, outputDir)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected cancelled extraction. Actual: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "version.txt")); err == nil {
+		t.Errorf("Files were extracted after cancel")
+	}
+}
+
+func createZipWithHeaders(t *testing.T, headers []*zip.FileHeader, contents []string) string {
+	archivePath := filepath.Join(t.TempDir(), "test_archive.zip")
+
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Can't create test archive. Error: %s", err.Error())
+	}
+	defer archive.Close()
+
+	zipWriter := zip.NewWriter(archive)
+	defer zipWriter.Close()
+
+	for i, header := range headers {
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("Can't add test file [%s] to archive. Error: %s", header.Name, err.Error())
+		}
+		writer.Write([]byte(contents[i]))
+	}
+
+	return archivePath
+}
+
+func TestUnzipRejectsUnsafeEntries(t *testing.T) {
+	symlinkHeader := &zip.FileHeader{Name: "vuetorrent/link"}
+	symlinkHeader.SetMode(os.ModeSymlink | 0777)
+
+	tests := map[string]*zip.FileHeader{
+		"parent directory traversal": {Name: "vuetorrent/../../evil.js"},
+		"traversal without prefix":   {Name: "../evil.js"},
+		"absolute path":              {Name: "/tmp/evil.js"},
+		"symlink":                    symlinkHeader,
+	}
+
+	for name, header := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			archivePath := createZipWithHeaders(t, []*zip.FileHeader{header}, []string{"evil"})
+			parentDir := t.TempDir()
+			outputDir := filepath.Join(parentDir, "out", "test_out")
+
+			// Run
+			err := DefaultUnzipper{}.Unzip(context.Background(), archivePath, outputDir)
+			if err == nil {
+				t.Fatalf("Expected entry [%s] to be rejected", header.Name)
+			}
+
+			if _, err := os.Stat(filepath.Join(parentDir, "evil.js")); err == nil {
+				t.Errorf("File was written outside of output directory")
+			}
+		})
+	}
+}
+
+func TestUnzipEnforcesLimits(t *testing.T) {
+	// Setup
+	files := []TestFile{
+		{Path: "vuetorrent/file1.js", Content: strings.Repeat("a", 600)},
+		{Path: "vuetorrent/file2.js", Content: strings.Repeat("b", 600)},
+		{Path: "vuetorrent/file3.js", Content: "c"},
+	}
+	archivePath := createZip(t, files)
+
+	tests := map[string]DefaultUnzipper{
+		"too many files":  {MaxFiles: 2},
+		"total size":      {MaxTotalSize: 1000},
+		"size of 1 entry": {MaxTotalSize: 500},
+	}
+
+	for name, unzipper := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Run
+			err := unzipper.Unzip(context.Background(), archivePath, filepath.Join(t.TempDir(), "test_out"))
+			if err == nil {
+				t.Fatal("Expected limit error")
+			}
+		})
+	}
+
+	// Archive within limits is extracted
+	err := DefaultUnzipper{MaxFiles: 3, MaxTotalSize: 1201}.Unzip(context.Background(), archivePath, filepath.Join(t.TempDir(), "test_out"))
+	if err != nil {
+		t.Fatalf("Archive within limits was rejected. Error: %s", err.Error())
+	}
+}
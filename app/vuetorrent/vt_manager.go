@@ -1,160 +1,567 @@
 package vuetorrent
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"n1kit0s/vt-manager/app/github"
+	"n1kit0s/vt-manager/app/httpclient"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type Release struct {
 	Version     string
 	DownloadUrl string
+	Prerelease  bool
+	PublishedAt time.Time
+	AssetName   string
+	// Size of the archive in bytes. Zero if unknown.
+	Size int64
+	// Digest of the archive in <algorithm>:<hex> form. Empty if not published.
+	Digest string
+	// ChecksumUrl points to a checksum file (e.g. SHA256SUMS) published with the release.
+	ChecksumUrl string
+	// SignatureUrls point to detached signatures of the archive (<asset>.minisig, .sig or .asc).
+	SignatureUrls []string
 }
 
 type VTManager interface {
-	GetLatestRelease() (Release, error)
-	GetReleaseByTag(tag string) (Release, error)
-	GetAllReleases() ([]Release, error)
-	GetReleaseForVersion(version string) (Release, error)
-	Install(version string, outputDir string) error
+	GetLatestRelease(ctx context.Context) (Release, error)
+	GetReleaseByTag(ctx context.Context, tag string) (Release, error)
+	GetAllReleases(ctx context.Context) ([]Release, error)
+	GetReleaseForVersion(ctx context.Context, version string) (Release, error)
+	// GetReleasesForConstraint returns all releases matching the version constraint. Empty constraint matches all.
+	GetReleasesForConstraint(ctx context.Context, constraint string) ([]Release, error)
+	Install(ctx context.Context, version string, outputDir string) (InstallResult, error)
+	// InstallArchive installs a local archive without looking up releases. If version is empty,
+	// it's read from the archive.
+	InstallArchive(ctx context.Context, filePath string, version string, outputDir string) (InstallResult, error)
+	// InstallFromUrl downloads an archive from downloadUrl and installs it like InstallArchive.
+	InstallFromUrl(ctx context.Context, downloadUrl string, version string, outputDir string) (InstallResult, error)
+	Check(ctx context.Context, version string, outputDir string) (CheckResult, error)
+}
+
+type UpdateStatus string
+
+const (
+	StatusUpToDate        UpdateStatus = "up-to-date"
+	StatusUpdateAvailable UpdateStatus = "update-available"
+	StatusNotInstalled    UpdateStatus = "not-installed"
+	// StatusNewerInstalled means the installed version is newer than the selected release,
+	// e.g. a prerelease is installed and the stable channel is checked.
+	StatusNewerInstalled UpdateStatus = "newer-installed"
+)
+
+// CheckResult compares the installed version with the release Install would pick.
+type CheckResult struct {
+	// InstalledVersion is empty if VueTorrent isn't installed.
+	InstalledVersion string
+	AvailableVersion string
+	Status           UpdateStatus
+}
+
+// InstallResult describes what Install did.
+type InstallResult struct {
+	// PreviousVersion is the version installed before. Empty if VueTorrent wasn't installed.
+	PreviousVersion string
+	Version         string
+	Directory       string
+	// Changed is false if the target version was already installed.
+	Changed bool
+}
+
+// InstallInfo describes an installed VueTorrent.
+type InstallInfo struct {
+	Version     string
+	Directory   string
+	InstalledAt time.Time
+}
+
+type Config struct {
+	// BackupDir is where previous versions are kept. Empty means DefaultBackupDir of the install directory.
+	BackupDir string
+	// KeepBackups is the number of previous versions retained after an install.
+	KeepBackups int
+	// Channel limits releases used for listing and installation. Empty means ChannelStable.
+	Channel Channel
+	// AssetPattern selects the release asset to install. The zero value matches DefaultAssetName.
+	AssetPattern AssetPattern
+	// MaxExtractSize and MaxExtractFiles limit extracted archives. Zero means the DefaultUnzipper defaults.
+	MaxExtractSize  int64
+	MaxExtractFiles int
+	// HttpClient executes downloads. Nil means http.DefaultClient.
+	HttpClient *http.Client
+	// OnProgress receives download progress events. It may be nil.
+	OnProgress ProgressFunc
+	// LimitRate is the maximum download speed in bytes per second. Zero means no limit.
+	LimitRate int64
+	// ArchiveCache keeps downloaded archives for reuse. Nil means archives are downloaded into
+	// DownloadDir and removed after install.
+	ArchiveCache *ArchiveCache
+	// DownloadDir keeps unfinished downloads without ArchiveCache, so an interrupted download is
+	// resumed by the next run. Empty means a temporary directory, which is removed after install.
+	DownloadDir string
+	// SignatureVerifier checks the archive signature before extraction. Nil means signatures aren't checked.
+	SignatureVerifier SignatureVerifier
+	// RequireSignature refuses to install archives without a valid signature.
+	RequireSignature bool
 }
 
 type vtManager struct {
-	githubClient github.Client
-	unzipper     Unzipper
-	downloader   Downloader
+	provider   ReleaseProvider
+	unzipper   Unzipper
+	downloader Downloader
+	config     Config
 }
 
-func NewVTManager(githubClient github.Client) VTManager {
+func NewVTManager(provider ReleaseProvider, config Config) VTManager {
 	return &vtManager{
-		githubClient: githubClient,
-		unzipper:     DefaultUnzipper{},
-		downloader:   HttpDownloader{},
+		provider:   provider,
+		unzipper:   DefaultUnzipper{MaxTotalSize: config.MaxExtractSize, MaxFiles: config.MaxExtractFiles},
+		downloader: HttpDownloader{Client: config.HttpClient, OnProgress: config.OnProgress, LimitRate: config.LimitRate},
+		config:     config,
 	}
 }
 
-func convertToVuetorrentRelease(githubRelease github.Release) Release {
-	var version, _ = strings.CutPrefix(githubRelease.TagName, "v")
+func convertToVuetorrentRelease(sourceRelease SourceRelease, assetPattern AssetPattern) Release {
+	var version, _ = strings.CutPrefix(sourceRelease.TagName, "v")
 
-	var downloadUrl string
-	for _, asset := range githubRelease.Assets {
-		if asset.Name == "vuetorrent.zip" {
-			downloadUrl = asset.DownloadUrl
+	var release = Release{
+		Version:     version,
+		Prerelease:  sourceRelease.Prerelease,
+		PublishedAt: sourceRelease.PublishedAt,
+	}
+
+	for _, asset := range sourceRelease.Assets {
+		if assetPattern.Match(asset.Name) {
+			release.DownloadUrl = asset.DownloadUrl
+			release.AssetName = asset.Name
+			release.Size = asset.Size
+			release.Digest = asset.Digest
 			break
 		}
 	}
 
-	return Release{
-		Version:     version,
-		DownloadUrl: downloadUrl,
+	for _, asset := range sourceRelease.Assets {
+		if release.AssetName != "" && isChecksumAsset(asset.Name, release.AssetName) {
+			release.ChecksumUrl = asset.DownloadUrl
+			break
+		}
 	}
+
+	for _, asset := range sourceRelease.Assets {
+		if release.AssetName != "" && isSignatureAsset(asset.Name, release.AssetName) {
+			release.SignatureUrls = append(release.SignatureUrls, asset.DownloadUrl)
+		}
+	}
+
+	return release
 }
 
-func (mng *vtManager) GetReleaseByTag(tag string) (Release, error) {
-	githubRelease, err := mng.githubClient.GetReleaseByTag(tag)
+func (mng *vtManager) GetReleaseByTag(ctx context.Context, tag string) (Release, error) {
+	sourceRelease, err := mng.provider.GetReleaseByTag(ctx, tag)
 	if err != nil {
 		return Release{}, err
 	}
 
-	vtRelease := convertToVuetorrentRelease(githubRelease)
+	vtRelease := convertToVuetorrentRelease(sourceRelease, mng.config.AssetPattern)
 
 	return vtRelease, nil
 }
 
-func (mng *vtManager) GetLatestRelease() (Release, error) {
-	githubReleases, err := mng.githubClient.GetReleases()
+// GetLatestRelease returns the release with the highest version in the configured channel.
+func (mng *vtManager) GetLatestRelease(ctx context.Context) (Release, error) {
+	vtReleases, err := mng.GetAllReleases(ctx)
 	if err != nil {
 		return Release{}, err
 	}
 
-	var latestRelease = githubReleases[0]
-	vtRelease := convertToVuetorrentRelease(latestRelease)
+	vtRelease, err := latestRelease(vtReleases)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to find latest %s release. %s", mng.channel(), err.Error())
+	}
 
 	return vtRelease, nil
 }
 
-func (mng *vtManager) GetAllReleases() ([]Release, error) {
-	githubReleases, err := mng.githubClient.GetReleases()
+// GetAllReleases returns published releases of the configured channel. Drafts are always skipped.
+func (mng *vtManager) GetAllReleases(ctx context.Context) ([]Release, error) {
+	sourceReleases, err := mng.provider.GetReleases(ctx)
 	if err != nil {
 		return []Release{}, err
 	}
 
 	var vtReleases []Release
+	var channel = mng.channel()
+
+	for _, sourceRelease := range sourceReleases {
+		if sourceRelease.Draft {
+			continue
+		}
 
-	for _, githubRelease := range githubReleases {
-		vtRelease := convertToVuetorrentRelease(githubRelease)
+		vtRelease := convertToVuetorrentRelease(sourceRelease, mng.config.AssetPattern)
+		if !channel.Includes(vtRelease) {
+			continue
+		}
 		vtReleases = append(vtReleases, vtRelease)
 	}
 
 	return vtReleases, nil
 }
 
-func (mng *vtManager) Install(targetVersion string, outputDir string) error {
-	release, err := mng.GetReleaseForVersion(targetVersion)
-	if err != nil {
-		return err
+func (mng *vtManager) channel() Channel {
+	if mng.config.Channel == "" {
+		return ChannelStable
 	}
+	return mng.config.Channel
+}
 
-	installedVersion, _ := GetInstalledVersion(outputDir)
+func (mng *vtManager) Install(ctx context.Context, targetVersion string, outputDir string) (InstallResult, error) {
+	cleanedOutputDir := filepath.Clean(outputDir)
+	var result = InstallResult{Directory: cleanedOutputDir}
 
-	slog.Info(fmt.Sprintf("Installed version: %s. Target version: %s", installedVersion, release.Version))
+	release, err := mng.GetReleaseForVersion(ctx, targetVersion)
+	if err != nil {
+		return result, err
+	}
+
+	check := checkInstalledVersion(outputDir, release)
+	result.PreviousVersion = check.InstalledVersion
+	result.Version = release.Version
 
-	if installedVersion == release.Version {
+	if check.Status == StatusUpToDate {
 		slog.Info(fmt.Sprintf("Version %s already installed. Abort installation", release.Version))
-		return nil
+		result.Version = check.InstalledVersion
+		return result, nil
+	}
+
+	// Only an exact version downgrades, a channel or a constraint never does
+	if check.Status == StatusNewerInstalled && !isExactVersion(targetVersion) {
+		slog.Info(fmt.Sprintf("Installed version %s is newer than %s. Abort installation", check.InstalledVersion, release.Version))
+		result.Version = check.InstalledVersion
+		return result, nil
+	}
+
+	if release.DownloadUrl == "" {
+		return result, fmt.Errorf("release %s has no asset matching [%s]", release.Version, mng.config.AssetPattern.String())
 	}
 
 	slog.Info("Start downloading", "release", release)
-	cleanedOutputDir := filepath.Clean(outputDir)
-	filePath, err := mng.downloader.Download(release, os.TempDir())
+	filePath, cleanup, err := mng.download(ctx, release)
 	if err != nil {
-		return err
+		return result, err
 	}
+	defer cleanup()
 	slog.Info("Downloaded release", "downloadPath", filePath)
 
-	var backupedDir, backupErr = backupPreviousVersion(cleanedOutputDir)
+	return mng.installDownloaded(ctx, filePath, release, result)
+}
 
-	err = mng.unzipper.Unzip(filePath, cleanedOutputDir)
-	if err != nil {
-		return err
+// download returns the release archive and a function releasing it after install.
+// Cached archives are pruned instead of being deleted.
+func (mng *vtManager) download(ctx context.Context, release Release) (string, func(), error) {
+	if mng.config.ArchiveCache == nil && mng.config.DownloadDir != "" {
+		if err := os.MkdirAll(mng.config.DownloadDir, os.ModePerm); err != nil {
+			return "", nil, err
+		}
+
+		// A failed download leaves its .part file here for the next run
+		filePath, err := mng.downloader.Download(ctx, release, mng.config.DownloadDir)
+		if err != nil {
+			return "", nil, err
+		}
+		return filePath, func() { os.Remove(filePath) }, nil
 	}
 
-	if backupErr == nil {
-		os.RemoveAll(backupedDir)
-		slog.Info("Removed old dir", "dir", backupedDir)
+	if mng.config.ArchiveCache == nil {
+		tempDir, err := os.MkdirTemp("", "vt-manager-")
+		if err != nil {
+			return "", nil, err
+		}
+
+		filePath, err := mng.downloader.Download(ctx, release, tempDir)
+		if err != nil {
+			os.RemoveAll(tempDir)
+			return "", nil, err
+		}
+		return filePath, func() { os.RemoveAll(tempDir) }, nil
 	}
 
-	err = createVersionFile(release.Version, cleanedOutputDir)
+	archiveCache := mng.config.ArchiveCache
+	filePath, err := archiveCache.Get(ctx, release, mng.downloader)
 	if err != nil {
-		slog.Warn("Can't create version file", "error", err.Error())
+		return "", nil, err
 	}
 
-	return nil
+	return filePath, func() {
+		if _, err := archiveCache.Prune(); err != nil {
+			slog.Warn("Can't prune archive cache", "error", err.Error())
+		}
+	}, nil
 }
 
-func (mng *vtManager) GetReleaseForVersion(version string) (Release, error) {
-	var vtRelease Release
+func (mng *vtManager) InstallArchive(ctx context.Context, filePath string, version string, outputDir string) (InstallResult, error) {
+	var result = InstallResult{Directory: filepath.Clean(outputDir)}
 
+	archiveVersion, archiveErr := ArchiveVersion(filePath)
 	if version == "" {
-		release, err := mng.GetLatestRelease()
-		if err != nil {
-			return Release{}, err
+		if archiveErr != nil {
+			return result, fmt.Errorf("failed to get version of %s. Pass it explicitly. %s", filePath, archiveErr.Error())
 		}
-		vtRelease = release
+		version = archiveVersion
 	} else {
-		tag := MakeTagName(version)
-		release, err := mng.GetReleaseByTag(tag)
+		if err := validateArchiveVersion(version); err != nil {
+			return result, err
+		}
+		// The installed version.txt comes from the archive, so a different version would be checked later
+		if archiveErr == nil && !SameVersion(version, archiveVersion) {
+			return result, fmt.Errorf("version %s doesn't match version %s of %s", version, archiveVersion, filePath)
+		}
+	}
+	if err := validateVersion(version); err != nil {
+		return result, fmt.Errorf("can't install %s. %s", filePath, err.Error())
+	}
+
+	var release = Release{Version: version, AssetName: filepath.Base(filePath)}
+
+	check := checkInstalledVersion(outputDir, release)
+	result.PreviousVersion = check.InstalledVersion
+	result.Version = release.Version
+
+	if check.Status == StatusUpToDate {
+		slog.Info(fmt.Sprintf("Version %s already installed. Abort installation", release.Version))
+		result.Version = check.InstalledVersion
+		return result, nil
+	}
+
+	return mng.installDownloaded(ctx, filePath, release, result)
+}
+
+func (mng *vtManager) InstallFromUrl(ctx context.Context, downloadUrl string, version string, outputDir string) (InstallResult, error) {
+	if version != "" {
+		if err := validateArchiveVersion(version); err != nil {
+			return InstallResult{Directory: filepath.Clean(outputDir)}, err
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "vt-manager-")
+	if err != nil {
+		return InstallResult{Directory: filepath.Clean(outputDir)}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	// The downloader names the file after the version, which may be unknown until the archive is read
+	var release = Release{Version: version, DownloadUrl: downloadUrl, AssetName: path.Base(downloadUrl)}
+	if release.Version == "" {
+		release.Version = "unknown"
+	}
+
+	slog.Info("Start downloading", "url", downloadUrl)
+	filePath, err := mng.downloader.Download(ctx, release, tempDir)
+	if err != nil {
+		return InstallResult{Directory: filepath.Clean(outputDir)}, err
+	}
+
+	return mng.InstallArchive(ctx, filePath, version, outputDir)
+}
+
+// validateArchiveVersion checks a version passed for an archive, which is installed as is.
+func validateArchiveVersion(version string) error {
+	if !isExactVersion(version) {
+		return fmt.Errorf("version constraint [%s] can't be used for an archive. Pass an exact version", version)
+	}
+	return validateVersion(version)
+}
+
+// installDownloaded extracts a verified archive into a staging directory and swaps it with the current install.
+func (mng *vtManager) installDownloaded(ctx context.Context, filePath string, release Release, result InstallResult) (InstallResult, error) {
+	cleanedOutputDir := result.Directory
+
+	if err := mng.verifySignature(ctx, filePath, release); err != nil {
+		return result, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cleanedOutputDir), os.ModePerm); err != nil {
+		return result, err
+	}
+
+	stagingDir, err := newStagingDir(cleanedOutputDir)
+	if err != nil {
+		return result, err
+	}
+
+	if err := mng.extractToStaging(ctx, filePath, stagingDir, release); err != nil {
+		os.RemoveAll(stagingDir)
+		return result, err
+	}
+
+	backups := NewBackupStore(cleanedOutputDir, mng.config.BackupDir, mng.config.KeepBackups)
+	backup, err := swapInstall(stagingDir, cleanedOutputDir, backups)
+	if err != nil {
+		os.RemoveAll(stagingDir)
+		return result, err
+	}
+
+	if backup.Path != "" {
+		slog.Info("Previous version backed up", "version", backup.Version, "backup", backup.Path)
+	}
+	if err := backups.Prune(); err != nil {
+		slog.Warn("Can't prune backups", "error", err.Error())
+	}
+
+	slog.Info("Installation completed", "version", release.Version, "dir", cleanedOutputDir)
+	result.Changed = true
+	return result, nil
+}
+
+// Check reports whether Install would change the installed version without installing anything.
+func (mng *vtManager) Check(ctx context.Context, targetVersion string, outputDir string) (CheckResult, error) {
+	release, err := mng.GetReleaseForVersion(ctx, targetVersion)
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	return checkInstalledVersion(outputDir, release), nil
+}
+
+func checkInstalledVersion(outputDir string, release Release) CheckResult {
+	var result = CheckResult{AvailableVersion: release.Version}
+
+	installedVersion, err := GetInstalledVersion(outputDir)
+	if err != nil {
+		slog.Info(fmt.Sprintf("VueTorrent is not installed. Target version: %s", release.Version))
+		result.Status = StatusNotInstalled
+		return result
+	}
+	result.InstalledVersion = installedVersion
+
+	slog.Info(fmt.Sprintf("Installed version: %s. Target version: %s", installedVersion, release.Version))
+
+	switch {
+	case SameVersion(installedVersion, release.Version):
+		result.Status = StatusUpToDate
+	case isNewerVersion(installedVersion, release.Version):
+		result.Status = StatusNewerInstalled
+	default:
+		result.Status = StatusUpdateAvailable
+	}
+	return result
+}
+
+// isNewerVersion is false if either version isn't a semantic version.
+func isNewerVersion(version string, than string) bool {
+	aVersion, aErr := ParseSemVer(version)
+	bVersion, bErr := ParseSemVer(than)
+	if aErr != nil || bErr != nil {
+		return false
+	}
+	return bVersion.LessThan(aVersion)
+}
+
+// isExactVersion reports whether version selects a single release like GetReleaseForVersion does.
+func isExactVersion(version string) bool {
+	if version == "" {
+		return false
+	}
+	constraint, err := ParseConstraint(version)
+	if err != nil {
+		return true
+	}
+	_, ok := constraint.Exact()
+	return ok
+}
+
+// GetReleaseForVersion resolves an install target. An empty version means the latest release,
+// an exact version is looked up by tag and a constraint (e.g. "~2.3", "^2", ">=2.1 <3", "2.x")
+// selects the highest matching release.
+func (mng *vtManager) GetReleaseForVersion(ctx context.Context, version string) (Release, error) {
+	if version == "" {
+		return mng.GetLatestRelease(ctx)
+	}
+
+	constraint, err := ParseConstraint(version)
+	if err != nil {
+		// Not a version constraint, so treat it as a plain tag name
+		return mng.getReleaseByVersion(ctx, version)
+	}
+
+	if exactVersion, ok := constraint.Exact(); ok {
+		return mng.getReleaseByVersion(ctx, exactVersion.String())
+	}
+
+	releases, err := mng.GetAllReleases(ctx)
+	if err != nil {
+		return Release{}, err
+	}
+
+	release, found := findHighestMatchingRelease(releases, constraint)
+	if !found {
+		return Release{}, fmt.Errorf("no release matches version constraint [%s]", version)
+	}
+
+	slog.Info("Resolved version constraint", "constraint", version, "version", release.Version)
+	return release, nil
+}
+
+func (mng *vtManager) getReleaseByVersion(ctx context.Context, version string) (Release, error) {
+	release, err := mng.GetReleaseByTag(ctx, MakeTagName(version))
+	if errors.Is(err, httpclient.ErrNotFound) {
+		return Release{}, fmt.Errorf("release %s not found. Run 'list' to see available versions. %w", version, err)
+	}
+	return release, err
+}
+
+func (mng *vtManager) GetReleasesForConstraint(ctx context.Context, constraint string) ([]Release, error) {
+	releases, err := mng.GetAllReleases(ctx)
+	if err != nil || constraint == "" {
+		return releases, err
+	}
+
+	parsedConstraint, err := ParseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []Release
+	for _, release := range releases {
+		version, err := ParseSemVer(release.Version)
+		if err == nil && parsedConstraint.Check(version) {
+			matching = append(matching, release)
+		}
+	}
+
+	return matching, nil
+}
+
+func findHighestMatchingRelease(releases []Release, constraint Constraint) (Release, bool) {
+	var bestRelease Release
+	var bestVersion SemVer
+	var found = false
+
+	for _, release := range releases {
+		version, err := ParseSemVer(release.Version)
 		if err != nil {
-			return Release{}, err
+			slog.Debug("Skipping release with invalid version", "version", release.Version)
+			continue
+		}
+
+		if !constraint.Check(version) {
+			continue
+		}
+
+		if !found || bestVersion.LessThan(version) {
+			bestRelease = release
+			bestVersion = version
+			found = true
 		}
-		vtRelease = release
 	}
 
-	return vtRelease, nil
+	return bestRelease, found
 }
 
 func createVersionFile(version string, outputDir string) error {
@@ -166,6 +573,11 @@ func createVersionFile(version string, outputDir string) error {
 	}
 
 	slog.Info("Creating missed version.txt file")
+	return writeVersionFile(version, outputDir)
+}
+
+func writeVersionFile(version string, outputDir string) error {
+	filePath := filepath.Join(filepath.Clean(outputDir), "version.txt")
 	versionData := []byte(version)
 	err := os.WriteFile(filePath, versionData, 0777)
 	if err != nil {
@@ -182,6 +594,26 @@ func MakeTagName(version string) string {
 	}
 }
 
+// GetInstallInfo returns the installed version. The install time is the modification time of the version file.
+func GetInstallInfo(vtDirectory string) (InstallInfo, error) {
+	version, err := GetInstalledVersion(vtDirectory)
+	if err != nil {
+		return InstallInfo{}, err
+	}
+
+	info, err := os.Stat(path.Join(vtDirectory, "version.txt"))
+	if err != nil {
+		return InstallInfo{}, err
+	}
+
+	directory, err := filepath.Abs(vtDirectory)
+	if err != nil {
+		return InstallInfo{}, err
+	}
+
+	return InstallInfo{Version: version, Directory: directory, InstalledAt: info.ModTime()}, nil
+}
+
 func GetInstalledVersion(vtDirectory string) (string, error) {
 	var versionFilePath = path.Join(vtDirectory, "version.txt")
 	_, err := os.Stat(versionFilePath)
@@ -194,23 +626,6 @@ func GetInstalledVersion(vtDirectory string) (string, error) {
 		return "unknown", err
 	}
 
-	return string(fileBytes), nil
-}
-
-func backupPreviousVersion(outputDir string) (string, error) {
-	_, err := os.Stat(outputDir)
-	var backupedDir = ""
-	if err == nil {
-		previousVersion, err := GetInstalledVersion(outputDir)
-		if err != nil {
-			slog.Warn("Previous version is unknown", "error", err.Error())
-		}
-		backupedDir = fmt.Sprintf(outputDir + "-" + previousVersion)
-		slog.Info("Renaming old output directory", "renamedDir", backupedDir)
-		if err := os.Rename(outputDir, backupedDir); err != nil {
-			return backupedDir, err
-		}
-	}
-
-	return backupedDir, err
+	// version.txt shipped inside the archive ends with a newline
+	return strings.TrimSpace(string(fileBytes)), nil
 }
@@ -1,24 +1,26 @@
 package vuetorrent
 
 import (
+	"context"
 	"fmt"
 	"n1kit0s/vt-manager/app/github"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
 type mockGithubClient struct {
 }
 
-func (c *mockGithubClient) GetReleases() ([]github.Release, error) {
+func (c *mockGithubClient) GetReleases(ctx context.Context) ([]github.Release, error) {
 	return []github.Release{
 		{TagName: "v1.1.3", Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-113.zip"}}},
 		{TagName: "v1.1.2", Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-112.zip"}}},
 		{TagName: "v1.1.1", Assets: []github.Asset{{Name: "vuetorrent.zip", DownloadUrl: "http://localhost:9876/dw/vuetorrent-111.zip"}}},
 	}, nil
 }
-func (c *mockGithubClient) GetReleaseByTag(tag string) (github.Release, error) {
+func (c *mockGithubClient) GetReleaseByTag(ctx context.Context, tag string) (github.Release, error) {
 	if tag == "v0.0.0" {
 		return github.Release{}, fmt.Errorf("tag %s not found", tag)
 	}
@@ -30,17 +32,17 @@ func (c *mockGithubClient) GetReleaseByTag(tag string) (github.Release, error) {
 func TestGetAllReleases(t *testing.T) {
 	// Setup
 	githubClient := &mockGithubClient{}
-	vtManager := NewVTManager(githubClient)
+	vtManager := NewVTManager(GithubProvider{Client: githubClient}, Config{})
 	expectedReleases := []Release{
-		{Version: "1.1.3", DownloadUrl: "http://localhost:9876/dw/vuetorrent-113.zip"},
-		{Version: "1.1.2", DownloadUrl: "http://localhost:9876/dw/vuetorrent-112.zip"},
-		{Version: "1.1.1", DownloadUrl: "http://localhost:9876/dw/vuetorrent-111.zip"},
+		{Version: "1.1.3", DownloadUrl: "http://localhost:9876/dw/vuetorrent-113.zip", AssetName: "vuetorrent.zip"},
+		{Version: "1.1.2", DownloadUrl: "http://localhost:9876/dw/vuetorrent-112.zip", AssetName: "vuetorrent.zip"},
+		{Version: "1.1.1", DownloadUrl: "http://localhost:9876/dw/vuetorrent-111.zip", AssetName: "vuetorrent.zip"},
 	}
 
 	// Run
-	releases, _ := vtManager.GetAllReleases()
+	releases, _ := vtManager.GetAllReleases(context.Background())
 	for i, release := range releases {
-		if release != expectedReleases[i] {
+		if !reflect.DeepEqual(release, expectedReleases[i]) {
 			t.Errorf("Actual: %+v | Expected: %+v", release, expectedReleases[i])
 		}
 	}
@@ -49,15 +51,16 @@ func TestGetAllReleases(t *testing.T) {
 func TestGetLatestRelease(t *testing.T) {
 	// Setup
 	githubClient := &mockGithubClient{}
-	vtManager := NewVTManager(githubClient)
+	vtManager := NewVTManager(GithubProvider{Client: githubClient}, Config{})
 	expectedRelease := Release{
 		Version:     "1.1.3",
 		DownloadUrl: "http://localhost:9876/dw/vuetorrent-113.zip",
+		AssetName:   "vuetorrent.zip",
 	}
 
 	// Run
-	release, _ := vtManager.GetLatestRelease()
-	if release != expectedRelease {
+	release, _ := vtManager.GetLatestRelease(context.Background())
+	if !reflect.DeepEqual(release, expectedRelease) {
 		t.Errorf("Actual: %+v | Expected: %+v", release, expectedRelease)
 	}
 }
@@ -65,29 +68,33 @@ func TestGetLatestRelease(t *testing.T) {
 func TestGetReleaseByTag(t *testing.T) {
 	// Setup
 	githubClient := &mockGithubClient{}
-	vtManager := NewVTManager(githubClient)
+	vtManager := NewVTManager(GithubProvider{Client: githubClient}, Config{})
 	expectedRelease := Release{
 		Version:     "1.1.2",
 		DownloadUrl: "http://localhost:9876/dw/vuetorrent.zip",
+		AssetName:   "vuetorrent.zip",
 	}
 
 	// Run
-	release, _ := vtManager.GetReleaseByTag("1.1.2")
-	if release != expectedRelease {
+	release, _ := vtManager.GetReleaseByTag(context.Background(), "1.1.2")
+	if !reflect.DeepEqual(release, expectedRelease) {
 		t.Errorf("Actual: %+v | Expected: %+v", release, expectedRelease)
 	}
 }
 
 type mockDownloader struct{}
 
-func (m mockDownloader) Download(release Release, outputDir string) (filePath string, err error) {
+func (m mockDownloader) Download(ctx context.Context, release Release, outputDir string) (filePath string, err error) {
 	return "/some/file/path", nil
 }
 
 type mockUnziper struct{}
 
-func (m mockUnziper) Unzip(filePath string, outputDir string) error {
-	return os.MkdirAll(outputDir, os.ModePerm)
+func (m mockUnziper) Unzip(ctx context.Context, filePath string, outputDir string) error {
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, "index.html"), []byte("<html></html>"), 0644)
 }
 
 func TestGetReleaseForVersion(t *testing.T) {
@@ -101,6 +108,7 @@ func TestGetReleaseForVersion(t *testing.T) {
 			expectedRelease: Release{
 				Version:     "1.1.1",
 				DownloadUrl: "http://localhost:9876/dw/vuetorrent.zip",
+				AssetName:   "vuetorrent.zip",
 			},
 			expectedError: nil,
 		},
@@ -109,25 +117,49 @@ func TestGetReleaseForVersion(t *testing.T) {
 			expectedRelease: Release{},
 			expectedError:   fmt.Errorf("tag v0.0.0 not found"),
 		},
+		"version constraint": {
+			targetVersion: "~1.1",
+			expectedRelease: Release{
+				Version:     "1.1.3",
+				DownloadUrl: "http://localhost:9876/dw/vuetorrent-113.zip",
+				AssetName:   "vuetorrent.zip",
+			},
+			expectedError: nil,
+		},
+		"version range": {
+			targetVersion: ">=1.0 <1.1.3",
+			expectedRelease: Release{
+				Version:     "1.1.2",
+				DownloadUrl: "http://localhost:9876/dw/vuetorrent-112.zip",
+				AssetName:   "vuetorrent.zip",
+			},
+			expectedError: nil,
+		},
+		"version constraint without matches": {
+			targetVersion:   "^2",
+			expectedRelease: Release{},
+			expectedError:   fmt.Errorf("no release matches version constraint [^2]"),
+		},
 		"version has not specified": {
 			targetVersion: "",
 			expectedRelease: Release{
 				Version:     "1.1.3",
 				DownloadUrl: "http://localhost:9876/dw/vuetorrent-113.zip",
+				AssetName:   "vuetorrent.zip",
 			},
 			expectedError: nil,
 		},
 	}
 
 	vtManager := vtManager{
-		githubClient: &mockGithubClient{},
-		downloader:   mockDownloader{},
-		unzipper:     mockUnziper{},
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			actualRelease, err := vtManager.GetReleaseForVersion(test.targetVersion)
+			actualRelease, err := vtManager.GetReleaseForVersion(context.Background(), test.targetVersion)
 			if err != nil && test.expectedError == nil {
 				t.Fatalf("GetReleaseForVersion failed. Error: %s", err.Error())
 			}
@@ -136,8 +168,40 @@ func TestGetReleaseForVersion(t *testing.T) {
 				t.Fatalf("GetReleaseForVersion error doesn't match. Expected: %s | Actual: %s", test.expectedError.Error(), err.Error())
 			}
 
-			if actualRelease != test.expectedRelease {
-				t.Fatalf("Releases don't match. Expected: %s | Actual: %s", test.expectedRelease, actualRelease)
+			if !reflect.DeepEqual(actualRelease, test.expectedRelease) {
+				t.Fatalf("Releases don't match. Expected: %+v | Actual: %+v", test.expectedRelease, actualRelease)
+			}
+		})
+	}
+}
+
+func TestGetReleasesForConstraint(t *testing.T) {
+	vtManager := vtManager{provider: GithubProvider{Client: &mockGithubClient{}}}
+
+	tests := []struct {
+		constraint       string
+		expectedVersions []string
+	}{
+		{constraint: "", expectedVersions: []string{"1.1.3", "1.1.2", "1.1.1"}},
+		{constraint: ">=1.1.2", expectedVersions: []string{"1.1.3", "1.1.2"}},
+		{constraint: "1.1.1", expectedVersions: []string{"1.1.1"}},
+		{constraint: "^2", expectedVersions: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.constraint, func(t *testing.T) {
+			// Run
+			releases, err := vtManager.GetReleasesForConstraint(context.Background(), test.constraint)
+			if err != nil {
+				t.Fatalf("Can't get releases. Error: %s", err.Error())
+			}
+
+			var versions []string
+			for _, release := range releases {
+				versions = append(versions, release.Version)
+			}
+			if !reflect.DeepEqual(test.expectedVersions, versions) {
+				t.Fatalf("Versions don't match. Expected %v | Actual %v", test.expectedVersions, versions)
 			}
 		})
 	}
@@ -146,9 +210,9 @@ func TestGetReleaseForVersion(t *testing.T) {
 func TestInstall(t *testing.T) {
 	// Setup
 	vtManager := vtManager{
-		githubClient: &mockGithubClient{},
-		downloader:   mockDownloader{},
-		unzipper:     mockUnziper{},
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
 	}
 
 	expectedVersion := "1.1.1"
@@ -158,7 +222,7 @@ func TestInstall(t *testing.T) {
 	expectedVersionFilePath := filepath.Join(outputDir, "version.txt")
 
 	// Run
-	err := vtManager.Install(expectedVersion, outputDir)
+	_, err := vtManager.Install(context.Background(), expectedVersion, outputDir)
 	if err != nil {
 		t.Fatalf("Installation failed. Error: %s", err.Error())
 	}
@@ -174,3 +238,151 @@ func TestInstall(t *testing.T) {
 	}
 
 }
+
+func TestInstallResult(t *testing.T) {
+	// Setup
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
+	}
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+	createInstalledVersion(t, outputDir, "1.1.1")
+
+	// Run
+	result, err := vtManager.Install(context.Background(), "1.1.2", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	expected := InstallResult{PreviousVersion: "1.1.1", Version: "1.1.2", Directory: outputDir, Changed: true}
+	if result != expected {
+		t.Fatalf("Install result doesn't match. Expected %+v | Actual %+v", expected, result)
+	}
+
+	result, err = vtManager.Install(context.Background(), "1.1.2", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	expected = InstallResult{PreviousVersion: "1.1.2", Version: "1.1.2", Directory: outputDir, Changed: false}
+	if result != expected {
+		t.Fatalf("Install result doesn't match. Expected %+v | Actual %+v", expected, result)
+	}
+}
+
+func TestInstallDoesNotDowngrade(t *testing.T) {
+	// Setup
+	vtManager := vtManager{
+		provider:   GithubProvider{Client: &mockGithubClient{}},
+		downloader: mockDownloader{},
+		unzipper:   mockUnziper{},
+	}
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+	createInstalledVersion(t, outputDir, "1.2.0-beta")
+
+	// Run
+	result, err := vtManager.Install(context.Background(), "", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	expected := InstallResult{PreviousVersion: "1.2.0-beta", Version: "1.2.0-beta", Directory: outputDir, Changed: false}
+	if result != expected {
+		t.Fatalf("Install result doesn't match. Expected %+v | Actual %+v", expected, result)
+	}
+
+	// An exact version is installed even if it's older
+	result, err = vtManager.Install(context.Background(), "1.1.2", outputDir)
+	if err != nil {
+		t.Fatalf("Installation failed. Error: %s", err.Error())
+	}
+
+	expected = InstallResult{PreviousVersion: "1.2.0-beta", Version: "1.1.2", Directory: outputDir, Changed: true}
+	if result != expected {
+		t.Fatalf("Install result doesn't match. Expected %+v | Actual %+v", expected, result)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	vtManager := vtManager{provider: GithubProvider{Client: &mockGithubClient{}}}
+
+	tests := []struct {
+		name             string
+		targetVersion    string
+		installedVersion string
+		expected         CheckResult
+	}{
+		{
+			name:             "up to date",
+			installedVersion: "1.1.3",
+			expected:         CheckResult{InstalledVersion: "1.1.3", AvailableVersion: "1.1.3", Status: StatusUpToDate},
+		},
+		{
+			name:             "update available",
+			installedVersion: "1.1.1",
+			expected:         CheckResult{InstalledVersion: "1.1.1", AvailableVersion: "1.1.3", Status: StatusUpdateAvailable},
+		},
+		{
+			name:             "pinned version",
+			targetVersion:    "1.1.1",
+			installedVersion: "1.1.1",
+			expected:         CheckResult{InstalledVersion: "1.1.1", AvailableVersion: "1.1.1", Status: StatusUpToDate},
+		},
+		{
+			name:     "not installed",
+			expected: CheckResult{AvailableVersion: "1.1.3", Status: StatusNotInstalled},
+		},
+		{
+			name:             "newer prerelease installed",
+			installedVersion: "1.2.0-beta",
+			expected:         CheckResult{InstalledVersion: "1.2.0-beta", AvailableVersion: "1.1.3", Status: StatusNewerInstalled},
+		},
+		{
+			name:             "newer than constraint",
+			targetVersion:    "~1.1",
+			installedVersion: "1.2.0",
+			expected:         CheckResult{InstalledVersion: "1.2.0", AvailableVersion: "1.1.3", Status: StatusNewerInstalled},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+			if test.installedVersion != "" {
+				createInstalledVersion(t, outputDir, test.installedVersion)
+			}
+
+			// Run
+			result, err := vtManager.Check(context.Background(), test.targetVersion, outputDir)
+			if err != nil {
+				t.Fatalf("Check failed. Error: %s", err.Error())
+			}
+
+			if result != test.expected {
+				t.Fatalf("Check result doesn't match. Expected %+v | Actual %+v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetInstallInfo(t *testing.T) {
+	// Setup
+	outputDir := filepath.Join(t.TempDir(), "vuetorrent")
+	createInstalledVersion(t, outputDir, "1.1.1")
+
+	// Run
+	info, err := GetInstallInfo(outputDir)
+	if err != nil {
+		t.Fatalf("Can't get install info. Error: %s", err.Error())
+	}
+
+	if info.Version != "1.1.1" || info.Directory != outputDir || info.InstalledAt.IsZero() {
+		t.Fatalf("Unexpected install info %+v", info)
+	}
+
+	if _, err := GetInstallInfo(t.TempDir()); err == nil {
+		t.Fatal("Expected error for directory without VueTorrent")
+	}
+}
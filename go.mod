@@ -2,6 +2,14 @@ module n1kit0s/vt-manager
 
 go 1.21.4
 
-require github.com/jessevdk/go-flags v1.5.0
+require (
+	github.com/ProtonMail/go-crypto v1.1.6
+	github.com/jessevdk/go-flags v1.5.0
+	golang.org/x/crypto v0.17.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-require golang.org/x/sys v0.0.0-20210320140829-1e4c9ba3b0c4 // indirect
+require (
+	github.com/cloudflare/circl v1.3.7 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+)